@@ -0,0 +1,30 @@
+// Command auditverify walks the publish_audit hash chain and exits non-zero
+// when a row has been changed or removed.
+package main
+
+import (
+	"encoding/json"
+	"events-service/internal/config"
+	"events-service/internal/db"
+	"events-service/internal/events/repository"
+	"log"
+	"os"
+)
+
+func main() {
+	cfg := config.Load()
+	repo := repository.NewEventRepository(db.InitDB(cfg))
+
+	report, err := repo.VerifyPublishAuditChain()
+	if err != nil {
+		log.Fatalf("verify: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+
+	if !report.Valid {
+		os.Exit(1)
+	}
+}
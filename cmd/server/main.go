@@ -1,15 +1,25 @@
 package main
 
 import (
+	"context"
+	"events-service/internal/audit"
+	"events-service/internal/auth"
 	"events-service/internal/config"
 	"events-service/internal/db"
 	"events-service/internal/events/handlers"
+	"events-service/internal/events/service"
 	"events-service/internal/events/workers"
-	"time"
-
-	"github.com/gin-contrib/cors"
+	"events-service/internal/httpsec"
+	"events-service/internal/notify"
+	"events-service/internal/ratelimit"
+	"events-service/internal/scan"
+	"events-service/internal/storage"
+	"events-service/internal/tenant"
+	"fmt"
+	"log"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func main() {
@@ -18,38 +28,242 @@ func main() {
 
     r := gin.Default()
 
-    r.Use(cors.New(cors.Config{
-    AllowOrigins:     []string{"http://localhost:3000", "http://localhost:5173"}, // React dev servers
-    AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-    AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-    ExposeHeaders:    []string{"Content-Length", "ETag"},
-    AllowCredentials: true,
-    MaxAge: 12 * time.Hour,
-}))
+    r.Use(httpsec.Headers(httpsec.HeaderOptions{
+        HSTSMaxAge:            cfg.HSTSMaxAge,
+        ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+    }))
+
+    corsMW, err := httpsec.CORS(httpsec.CORSOptions{
+        AllowOrigins:     cfg.CORSAllowOrigins,
+        AllowMethods:     cfg.CORSAllowMethods,
+        AllowCredentials: cfg.CORSAllowCredentials,
+        MaxAge:           cfg.CORSMaxAge,
+    })
+    if err != nil {
+        log.Fatalf("config: %v", err)
+    }
+    if corsMW != nil {
+        r.Use(corsMW)
+    }
 
     h := handlers.NewEventHandler(database)
 
+    files, err := storage.New(context.Background(), storage.Config{
+        Backend:          cfg.StorageBackend,
+        LocalDir:         cfg.StorageLocalDir,
+        S3Bucket:         cfg.S3Bucket,
+        S3Region:         cfg.S3Region,
+        S3Endpoint:       cfg.S3Endpoint,
+        S3ForcePathStyle: cfg.S3ForcePathStyle,
+    })
+    if err != nil {
+        log.Fatalf("config: %v", err)
+    }
+    h.Service.Files = files
+    h.Service.Links = service.LinkPolicy{
+        TTL:        cfg.AttachmentLinkTTL,
+        MessageTTL: cfg.AttachmentMessageLinkTTL,
+    }
+    h.Service.Attachments = service.AttachmentPolicy{
+        AllowedTypes:     cfg.AttachmentAllowedTypes,
+        MaxFileBytes:     cfg.AttachmentMaxFileBytes,
+        MaxEventBytes:    cfg.AttachmentMaxEventBytes,
+        AuthorQuotaBytes: cfg.AttachmentAuthorQuota,
+        UnlinkedTTL:      cfg.AttachmentUnlinkedTTL,
+    }
+    if len(h.Service.Attachments.AllowedTypes) == 0 {
+        h.Service.Attachments.AllowedTypes = service.DefaultAttachmentTypes
+    }
+    if h.Service.Scanner, err = newScanner(cfg); err != nil {
+        log.Fatalf("config: %v", err)
+    }
+
+    channels, err := newChannels(cfg, h.Service)
+    if err != nil {
+        log.Fatalf("config: %v", err)
+    }
+    h.Service.Channels = channels
+
     // start broadcast worker
-    bw := workers.NewBroadcastWorker(h.Service)
+    bw := workers.NewBroadcastWorker(h.Service, channels)
+    bw.LeaseDuration = cfg.BroadcastLeaseTTL
+    bw.PollInterval = cfg.BroadcastPollInterval
+    bw.BatchSize = cfg.BroadcastBatchSize
+    bw.Concurrency = map[string]int{
+        "fcm":   cfg.BroadcastConcurrencyFCM,
+        "email": cfg.BroadcastConcurrencyEmail,
+        "teams": cfg.BroadcastConcurrencyTeams,
+    }
     bw.Start()
     // optionally: store bw to gracefully stop on shutdown
 
+    // rescan uploads the scanner could not reach at upload time
+    sw := workers.NewScanWorker(h.Service)
+    sw.Start()
+
+    // delete uploads nobody linked to an event
+    aw := workers.NewAttachmentSweeper(h.Service)
+    aw.Start()
+
+    verifier, err := auth.NewVerifier(auth.Config{
+        HMACSecret:       cfg.JWTSecret,
+        RSAPublicKeyFile: cfg.JWTPublicKeyFile,
+        JWKSFile:         cfg.JWTJWKSFile,
+        Issuer:           cfg.JWTIssuer,
+        Audience:         cfg.JWTAudience,
+    })
+    if err != nil {
+        log.Fatalf("auth: %v", err)
+    }
+
+    // rate limits: one bucket per caller per route group
+    var limits ratelimit.Store = ratelimit.NewMemoryStore()
+    if cfg.RateLimitStore == "postgres" {
+        limits = ratelimit.NewPostgresStore(database)
+    }
+    // runs before auth, so it keys on the client IP and also covers
+    // requests with bad credentials
+    limitIP := ratelimit.Middleware(limits, "ip", mustLimit(cfg.RateLimitIP))
+    limitAPI := ratelimit.Middleware(limits, "api", mustLimit(cfg.RateLimitDefault))
+    limitWrites := ratelimit.Middleware(limits, "events-write", mustLimit(cfg.RateLimitEventsWrite))
+    limitBroadcast := ratelimit.Middleware(limits, "broadcast", mustLimit(cfg.RateLimitBroadcast))
+
+    var defaultTenant uuid.UUID
+    if cfg.DefaultTenantID != "" {
+        if defaultTenant, err = uuid.Parse(cfg.DefaultTenantID); err != nil {
+            log.Fatalf("config: DEFAULT_TENANT_ID: %v", err)
+        }
+    }
 
     api := r.Group("/api/v1")
+    api.Use(
+        audit.RequestID(),
+        limitIP,
+        auth.Middleware(verifier, h.Service),
+        tenant.Middleware(h.Service, defaultTenant),
+        auth.ResolveRoles(h.Service),
+        audit.Middleware(h.Service, h.Service),
+        limitAPI,
+    )
+    can := h.Guard.Require
     {
-        api.GET("/events", h.ListEvents)
-        api.GET("/events/:id", h.GetEvent)
-        api.POST("/events", h.CreateEvent)
-        api.PATCH("/events/:id", h.UpdateEvent)
-        api.POST("/events/:id/moderate", h.ModerateEvent)
-        api.POST("/events/:id/broadcast", h.ManualBroadcast)
-        api.POST("/events/tag-suggest", h.TagSuggest)
-        api.GET("/tags", h.ListTags)
+        api.GET("/events", can(auth.PermEventsRead), h.ListEvents)
+        api.GET("/events/:id", can(auth.PermEventsRead), h.GetEvent)
+        api.GET("/events/:id/audience", can(auth.PermEventsWrite), h.PreviewAudience)
+        api.POST("/events", can(auth.PermEventsWrite), limitWrites, h.CreateEvent)
+        api.PATCH("/events/:id", can(auth.PermEventsWrite), limitWrites, h.UpdateEvent)
+        api.DELETE("/events/:id", can(auth.PermEventsLifecycle), h.DeleteEvent)
+        api.POST("/events/bulk", can(auth.PermEventsLifecycle), limitWrites, h.BulkEvents)
+        api.POST("/events/:id/moderate", can(auth.PermEventsModerate), h.ModerateEvent)
+        api.POST("/events/:id/broadcast", can(auth.PermBroadcastTrigger), limitBroadcast, h.ManualBroadcast)
+        api.POST("/events/:id/clone", can(auth.PermEventsWrite), limitWrites, h.CloneEvent)
+        api.POST("/events/:id/archive", can(auth.PermEventsLifecycle), h.ArchiveEvent)
+        api.POST("/events/:id/retract", can(auth.PermEventsLifecycle), limitBroadcast, h.RetractEvent)
+        api.POST("/events/tag-suggest", can(auth.PermEventsWrite), h.TagSuggest)
+        api.POST("/events/from-template/:id", can(auth.PermEventsWrite), limitWrites, h.CreateEventFromTemplate)
+        api.GET("/tags", can(auth.PermTagsRead), h.ListTags)
+
+        api.POST("/attachments", can(auth.PermEventsWrite), limitWrites, h.UploadAttachment)
+        api.GET("/attachments/:id", can(auth.PermEventsRead), h.DownloadAttachment)
+        api.DELETE("/attachments/:id", can(auth.PermEventsWrite), h.DeleteAttachment)
+
+        api.GET("/templates", can(auth.PermTemplatesRead), h.ListTemplates)
+        api.GET("/templates/:id", can(auth.PermTemplatesRead), h.GetTemplate)
+        api.POST("/templates", can(auth.PermTemplatesWrite), h.CreateTemplate)
+        api.PATCH("/templates/:id", can(auth.PermTemplatesWrite), h.UpdateTemplate)
+        api.DELETE("/templates/:id", can(auth.PermTemplatesWrite), h.DeleteTemplate)
+
+        api.GET("/admin/api-keys", can(auth.PermAPIKeysManage), h.ListAPIKeys)
+        api.POST("/admin/api-keys", can(auth.PermAPIKeysManage), h.CreateAPIKey)
+        api.DELETE("/admin/api-keys/:id", can(auth.PermAPIKeysManage), h.RevokeAPIKey)
+
+        api.GET("/admin/signing-keys", can(auth.PermSigningKeysManage), h.ListSigningKeys)
+        api.POST("/admin/signing-keys/rotate", can(auth.PermSigningKeysManage), h.RotateSigningKey)
+
+        api.GET("/admin/broadcasts", can(auth.PermBroadcastsManage), h.ListBroadcasts)
+        api.POST("/admin/broadcasts/bulk", can(auth.PermBroadcastsManage), h.BulkBroadcasts)
+        api.GET("/admin/broadcasts/:id", can(auth.PermBroadcastsManage), h.GetBroadcast)
+        api.POST("/admin/broadcasts/:id/retry", can(auth.PermBroadcastsManage), h.RetryBroadcast)
+        api.POST("/admin/broadcasts/:id/cancel", can(auth.PermBroadcastsManage), h.CancelBroadcast)
+        api.DELETE("/admin/broadcasts/:id", can(auth.PermBroadcastsManage), h.PurgeBroadcast)
+
+        api.GET("/audit", can(auth.PermAuditRead), h.ListAuditLog)
+        api.GET("/admin/audit/verify", can(auth.PermAuditRead), h.VerifyAuditChain)
     }
 
+    // covers of published events, fetched by push services and mail clients
+    // through signed links
+    r.GET("/media/covers/:id/:variant", h.CoverImage)
+
     r.GET("/healthz", func(c *gin.Context) {
         c.JSON(200, gin.H{"status": "ok"})
     })
 
-    r.Run(":" + cfg.Port)
+    if err := serve(r, cfg); err != nil {
+        log.Fatalf("server: %v", err)
+    }
+}
+
+// serve runs HTTPS when a certificate and key are configured, plain HTTP
+// otherwise (e.g. behind a TLS-terminating load balancer).
+func serve(r *gin.Engine, cfg *config.Config) error {
+    if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+        return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+    }
+    if cfg.TLSCertFile != "" {
+        return r.RunTLS(":"+cfg.Port, cfg.TLSCertFile, cfg.TLSKeyFile)
+    }
+    return r.Run(":" + cfg.Port)
+}
+
+// newChannels registers the notifiers the broadcast worker delivers through.
+func newChannels(cfg *config.Config, svc *service.EventService) (*notify.Registry, error) {
+    email, err := workers.NewEmailNotifier(context.Background(), svc, cfg.PublicBaseURL)
+    if err != nil {
+        return nil, err
+    }
+    email.Concurrency = cfg.SESSendConcurrency
+
+    channels := notify.NewRegistry()
+    for _, ch := range []struct {
+        notifier notify.Notifier
+        retry    string
+    }{
+        {workers.NewFCMNotifier(svc, cfg.PublicBaseURL), cfg.RetryPolicyFCM},
+        {email, cfg.RetryPolicyEmail},
+        {workers.NewTeamsNotifier(svc, cfg.PublicBaseURL), cfg.RetryPolicyTeams},
+    } {
+        policy, err := notify.ParseRetryPolicy(ch.retry)
+        if err != nil {
+            return nil, err
+        }
+        if err := channels.Register(ch.notifier, policy); err != nil {
+            return nil, err
+        }
+    }
+    return channels, nil
+}
+
+// newScanner picks the malware scanner. The fake only recognises test
+// signatures, so it is refused outside development.
+func newScanner(cfg *config.Config) (scan.Scanner, error) {
+    switch cfg.ScannerBackend {
+    case "clamd":
+        return &scan.Clamd{Network: cfg.ClamdNetwork, Address: cfg.ClamdAddress, Timeout: cfg.ScanTimeout}, nil
+    case "fake":
+        if cfg.Env != "development" {
+            return nil, fmt.Errorf("SCANNER_BACKEND=fake is only allowed in development")
+        }
+        return scan.NewFake(), nil
+    default:
+        return nil, fmt.Errorf("unknown SCANNER_BACKEND %q", cfg.ScannerBackend)
+    }
+}
+
+func mustLimit(spec string) ratelimit.Limit {
+    l, err := ratelimit.ParseLimit(spec)
+    if err != nil {
+        log.Fatalf("config: %v", err)
+    }
+    return l
 }
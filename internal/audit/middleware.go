@@ -0,0 +1,162 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"events-service/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Entry is one audited API call.
+type Entry struct {
+	TenantID      uuid.UUID
+	RequestID     string
+	ActorID       uuid.UUID
+	ActorKind     string
+	Method        string
+	Route         string
+	Path          string
+	TargetType    string
+	TargetID      string
+	StatusCode    int
+	ClientIP      string
+	RequestDigest string
+	BeforeDigest  string
+	AfterDigest   string
+	CreatedAt     time.Time
+}
+
+type Recorder interface {
+	RecordAPIAudit(e Entry) error
+}
+
+// Snapshotter returns the current state of a tenant's target so changes
+// can be digested. ok is false when the target does not exist.
+type Snapshotter interface {
+	Snapshot(tenantID uuid.UUID, targetType, targetID string) (state []byte, ok bool)
+}
+
+// maxCapturedBody bounds how much of a request or response is kept in
+// memory for digests and target id extraction.
+const maxCapturedBody = 1 << 20
+
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	if w.body.Len() < maxCapturedBody {
+		w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Middleware writes an audit entry for every mutating request, including
+// ones refused by permission checks or rate limits further down the chain.
+// Entries belong to the principal's tenant, so it must run after
+// tenant.Middleware.
+func Middleware(rec Recorder, snaps Snapshotter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		entry := Entry{
+			RequestID:  RequestIDFrom(c),
+			Method:     c.Request.Method,
+			Route:      c.FullPath(),
+			Path:       c.Request.URL.Path,
+			ClientIP:   c.ClientIP(),
+			TargetType: targetType(c.FullPath()),
+			TargetID:   c.Param("id"),
+		}
+		if p, ok := auth.FromContext(c); ok {
+			entry.TenantID = p.Tenant
+		}
+
+		if c.Request.Body != nil {
+			body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCapturedBody))
+			if err == nil {
+				entry.RequestDigest = digest(body)
+				c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+			}
+		}
+
+		paramID := entry.TargetID
+		if snaps != nil && paramID != "" {
+			if state, ok := snaps.Snapshot(entry.TenantID, entry.TargetType, paramID); ok {
+				entry.BeforeDigest = digest(state)
+			}
+		}
+
+		w := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		entry.StatusCode = c.Writer.Status()
+		entry.CreatedAt = time.Now()
+
+		if p, ok := auth.FromContext(c); ok {
+			entry.ActorID = p.Subject
+			entry.ActorKind = p.Kind
+		}
+
+		// creates (including clones) target the resource named in the response
+		var resp struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(w.body.Bytes(), &resp) == nil && resp.ID != "" && resp.ID != paramID {
+			entry.TargetID = resp.ID
+			entry.BeforeDigest = ""
+		}
+
+		if snaps != nil && entry.TargetID != "" && entry.StatusCode < http.StatusBadRequest {
+			if state, ok := snaps.Snapshot(entry.TenantID, entry.TargetType, entry.TargetID); ok {
+				entry.AfterDigest = digest(state)
+			}
+		}
+
+		if err := rec.RecordAPIAudit(entry); err != nil {
+			log.Printf("Audit: cannot record %s %s: %v\n", entry.Method, entry.Path, err)
+		}
+	}
+}
+
+// targetType names the resource a route acts on, e.g. "/api/v1/events/:id"
+// is an "event".
+func targetType(route string) string {
+	for _, p := range strings.Split(strings.Trim(route, "/"), "/") {
+		if p == "api" || p == "v1" || p == "admin" {
+			continue
+		}
+		name := strings.TrimSuffix(p, "s")
+		return strings.ReplaceAll(name, "-", "_")
+	}
+	return ""
+}
+
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
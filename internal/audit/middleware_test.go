@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"events-service/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryRecorder struct {
+	entries []Entry
+}
+
+func (m *memoryRecorder) RecordAPIAudit(e Entry) error {
+	m.entries = append(m.entries, e)
+	return nil
+}
+
+// memorySnaps serves the current title of each event as its state.
+type memorySnaps map[string]string
+
+func (m memorySnaps) Snapshot(_ uuid.UUID, targetType, targetID string) ([]byte, bool) {
+	state, ok := m[targetType+"/"+targetID]
+	return []byte(state), ok
+}
+
+func TestMiddlewareRecordsMutations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rec := &memoryRecorder{}
+	snaps := memorySnaps{"event/e1": "old title"}
+	actor := &auth.Principal{Subject: uuid.New(), Kind: auth.PrincipalUser, Tenant: uuid.New()}
+
+	r := gin.New()
+	r.Use(RequestID(), func(c *gin.Context) { c.Set(auth.PrincipalKey, actor) }, Middleware(rec, snaps))
+	r.GET("/api/v1/events/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.PATCH("/api/v1/events/:id", func(c *gin.Context) {
+		snaps["event/e1"] = "new title"
+		c.JSON(http.StatusOK, gin.H{"message": "event updated"})
+	})
+	r.POST("/api/v1/events", func(c *gin.Context) {
+		snaps["event/e2"] = "created"
+		c.JSON(http.StatusCreated, gin.H{"id": "e2"})
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/events/e1", nil))
+	assert.Empty(t, rec.entries, "reads are not audited")
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/events/e1", strings.NewReader(`{"title":"new title"}`))
+	req.Header.Set(RequestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "req-1", w.Header().Get(RequestIDHeader))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(`{}`)))
+
+	if assert.Len(t, rec.entries, 2) {
+		patch := rec.entries[0]
+		assert.Equal(t, "req-1", patch.RequestID)
+		assert.Equal(t, actor.Subject, patch.ActorID)
+		assert.Equal(t, actor.Tenant, patch.TenantID)
+		assert.Equal(t, "/api/v1/events/:id", patch.Route)
+		assert.Equal(t, "event", patch.TargetType)
+		assert.Equal(t, "e1", patch.TargetID)
+		assert.Equal(t, digest([]byte("old title")), patch.BeforeDigest)
+		assert.Equal(t, digest([]byte("new title")), patch.AfterDigest)
+		assert.Equal(t, digest([]byte(`{"title":"new title"}`)), patch.RequestDigest)
+
+		create := rec.entries[1]
+		assert.Equal(t, "e2", create.TargetID)
+		assert.Empty(t, create.BeforeDigest)
+		assert.Equal(t, digest([]byte("created")), create.AfterDigest)
+		assert.NotEmpty(t, create.RequestID)
+		assert.Equal(t, http.StatusCreated, create.StatusCode)
+	}
+}
+
+func TestTargetType(t *testing.T) {
+	assert.Equal(t, "event", targetType("/api/v1/events/:id/moderate"))
+	assert.Equal(t, "template", targetType("/api/v1/templates/:id"))
+	assert.Equal(t, "api_key", targetType("/api/v1/admin/api-keys/:id"))
+}
@@ -0,0 +1,29 @@
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	requestIDKey    = "audit.request_id"
+)
+
+// RequestID reuses the caller's X-Request-ID or assigns a new one, and
+// echoes it on the response.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = uuid.NewString()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+func RequestIDFrom(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
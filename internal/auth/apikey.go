@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyHeader carries service API keys used by machine clients.
+const APIKeyHeader = "X-API-Key"
+
+const apiKeyPrefix = "evk_"
+
+// Scopes an API key may be granted. They are checked like permissions.
+var APIKeyScopes = map[string]bool{
+	PermEventsWrite:      true,
+	PermBroadcastTrigger: true,
+	PermTagsRead:         true,
+}
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// APIKeyRecord is the stored form of a key; the plaintext is never kept.
+type APIKeyRecord struct {
+	ID         uuid.UUID
+	TenantID   uuid.UUID
+	Hash       string
+	Scopes     []string
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+type APIKeyStore interface {
+	LookupAPIKey(prefix string) (*APIKeyRecord, error)
+	TouchAPIKey(id uuid.UUID, at time.Time) error
+}
+
+// GenerateAPIKey returns a new plaintext key together with the lookup prefix
+// and hash to store. The plaintext is shown to the admin once.
+func GenerateAPIKey() (plaintext, prefix, hash string, err error) {
+	idBytes := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err = rand.Read(idBytes); err != nil {
+		return "", "", "", err
+	}
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(idBytes)
+	plaintext = apiKeyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return plaintext, prefix, HashAPIKey(plaintext), nil
+}
+
+func HashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseAPIKeyPrefix extracts the lookup prefix from evk_<prefix>_<secret>.
+func parseAPIKeyPrefix(plaintext string) (string, bool) {
+	rest, ok := strings.CutPrefix(plaintext, apiKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// authenticateAPIKey resolves a plaintext key to a Principal scoped to the
+// key's grants, touching its last-used time.
+func authenticateAPIKey(store APIKeyStore, plaintext string, now time.Time) (*Principal, error) {
+	prefix, ok := parseAPIKeyPrefix(plaintext)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	rec, err := store.LookupAPIKey(prefix)
+	if err != nil || rec == nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if subtle.ConstantTimeCompare([]byte(rec.Hash), []byte(HashAPIKey(plaintext))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	if rec.RevokedAt != nil || !now.Before(rec.ExpiresAt) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// last-used only needs minute precision; skip the write on busy keys
+	if rec.LastUsedAt == nil || now.Sub(*rec.LastUsedAt) > time.Minute {
+		_ = store.TouchAPIKey(rec.ID, now)
+	}
+
+	return &Principal{Subject: rec.ID, Kind: PrincipalAPIKey, Scopes: rec.Scopes, Tenant: rec.TenantID}, nil
+}
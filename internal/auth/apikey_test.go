@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryKeys struct {
+	byPrefix map[string]*APIKeyRecord
+	touched  int
+}
+
+func (m *memoryKeys) LookupAPIKey(prefix string) (*APIKeyRecord, error) {
+	rec, ok := m.byPrefix[prefix]
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	return rec, nil
+}
+
+func (m *memoryKeys) TouchAPIKey(id uuid.UUID, at time.Time) error {
+	m.touched++
+	for _, rec := range m.byPrefix {
+		if rec.ID == id {
+			rec.LastUsedAt = &at
+		}
+	}
+	return nil
+}
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	plaintext, prefix, hash, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	rec := &APIKeyRecord{
+		ID:        uuid.New(),
+		TenantID:  uuid.New(),
+		Hash:      hash,
+		Scopes:    []string{PermEventsWrite},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	keys := &memoryKeys{byPrefix: map[string]*APIKeyRecord{prefix: rec}}
+
+	v, err := NewVerifier(Config{HMACSecret: "unused"})
+	require.NoError(t, err)
+
+	g := NewGuard(nil)
+	r := gin.New()
+	r.Use(Middleware(v, keys))
+	r.POST("/events", g.Require(PermEventsWrite), func(c *gin.Context) {
+		p, _ := FromContext(c)
+		c.String(http.StatusCreated, p.Subject.String()+"@"+p.Tenant.String())
+	})
+	r.POST("/events/:id/broadcast", g.Require(PermBroadcastTrigger), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	call := func(path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set(APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := call("/events", plaintext)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, rec.ID.String()+"@"+rec.TenantID.String(), w.Body.String(), "the key names its tenant")
+	assert.NotNil(t, rec.LastUsedAt)
+
+	// second call within a minute does not write last_used_at again
+	call("/events", plaintext)
+	assert.Equal(t, 1, keys.touched)
+
+	assert.Equal(t, http.StatusForbidden, call("/events/1/broadcast", plaintext).Code, "scope not granted")
+	assert.Equal(t, http.StatusUnauthorized, call("/events", plaintext+"x").Code, "wrong secret")
+	assert.Equal(t, http.StatusUnauthorized, call("/events", "not-a-key").Code)
+
+	revokedAt := time.Now()
+	rec.RevokedAt = &revokedAt
+	assert.Equal(t, http.StatusUnauthorized, call("/events", plaintext).Code, "revoked")
+
+	rec.RevokedAt = nil
+	rec.ExpiresAt = time.Now().Add(-time.Second)
+	assert.Equal(t, http.StatusUnauthorized, call("/events", plaintext).Code, "expired")
+}
@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoKeys       = errors.New("auth: no token verification keys configured")
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// Config selects where token verification keys come from. Any combination
+// may be set; a JWKS entry matching the token's kid wins over the single keys.
+type Config struct {
+	HMACSecret       string // HS256 shared secret
+	RSAPublicKeyFile string // PEM encoded RS256 public key
+	JWKSFile         string // local JWKS document
+
+	Issuer   string // required iss, if set
+	Audience string // required aud, if set
+}
+
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles    []string `json:"roles,omitempty"`
+	TenantID string   `json:"tenant_id,omitempty"`
+}
+
+// Verifier validates bearer tokens signed with HS256 or RS256.
+type Verifier struct {
+	hmacSecret []byte
+	rsaKey     *rsa.PublicKey
+	jwks       *keySet
+
+	issuer   string
+	audience string
+}
+
+func NewVerifier(cfg Config) (*Verifier, error) {
+	v := &Verifier{
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}
+
+	if cfg.HMACSecret != "" {
+		v.hmacSecret = []byte(cfg.HMACSecret)
+	}
+
+	if cfg.RSAPublicKeyFile != "" {
+		pemBytes, err := os.ReadFile(cfg.RSAPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read rsa public key: %w", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse rsa public key: %w", err)
+		}
+		v.rsaKey = key
+	}
+
+	if cfg.JWKSFile != "" {
+		ks, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.jwks = ks
+	}
+
+	if v.hmacSecret == nil && v.rsaKey == nil && v.jwks == nil {
+		return nil, ErrNoKeys
+	}
+
+	return v, nil
+}
+
+// Verify checks the signature, expiry, issuer and audience of a raw token and
+// returns its claims.
+func (v *Verifier) Verify(raw string) (*Claims, error) {
+	var claims Claims
+
+	_, err := jwt.ParseWithClaims(raw, &claims, v.keyFor,
+		jwt.WithValidMethods([]string{"HS256", "RS256"}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if v.issuer != "" && !claims.VerifyIssuer(v.issuer, true) {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if v.audience != "" && !claims.VerifyAudience(v.audience, true) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+
+	return &claims, nil
+}
+
+func (v *Verifier) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case "HS256":
+		if v.jwks != nil && kid != "" {
+			if key, ok := v.jwks.hmac[kid]; ok {
+				return key, nil
+			}
+		}
+		if v.hmacSecret != nil {
+			return v.hmacSecret, nil
+		}
+
+	case "RS256":
+		if v.jwks != nil {
+			if key, ok := v.jwks.rsa[kid]; ok {
+				return key, nil
+			}
+		}
+		if v.rsaKey != nil {
+			return v.rsaKey, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no key for alg %s kid %q", token.Method.Alg(), kid)
+}
+
+// Principal builds the authenticated identity from verified claims. The
+// subject must be the caller's user id; tenant_id, when present, must be a
+// tenant id.
+func (c *Claims) Principal() (*Principal, error) {
+	sub, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: subject is not a user id", ErrInvalidToken)
+	}
+
+	var tenant uuid.UUID
+	if c.TenantID != "" {
+		if tenant, err = uuid.Parse(c.TenantID); err != nil {
+			return nil, fmt.Errorf("%w: tenant_id is not a tenant id", ErrInvalidToken)
+		}
+	}
+
+	return &Principal{Subject: sub, Kind: PrincipalUser, Roles: c.Roles, Tenant: tenant}, nil
+}
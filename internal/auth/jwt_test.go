@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signHS256(t *testing.T, secret string, claims jwt.Claims) string {
+	t.Helper()
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return tok
+}
+
+func userClaims(sub uuid.UUID, ttl time.Duration) jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   sub.String(),
+		Issuer:    "staff-idp",
+		Audience:  jwt.ClaimStrings{"events-service"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+	}
+}
+
+func TestVerifyHS256(t *testing.T) {
+	v, err := NewVerifier(Config{HMACSecret: "s3cret", Issuer: "staff-idp", Audience: "events-service"})
+	require.NoError(t, err)
+
+	sub := uuid.New()
+	claims, err := v.Verify(signHS256(t, "s3cret", userClaims(sub, time.Hour)))
+	require.NoError(t, err)
+
+	p, err := claims.Principal()
+	require.NoError(t, err)
+	assert.Equal(t, sub, p.Subject)
+
+	_, err = v.Verify(signHS256(t, "wrong", userClaims(sub, time.Hour)))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = v.Verify(signHS256(t, "s3cret", userClaims(sub, -time.Minute)))
+	assert.ErrorIs(t, err, ErrInvalidToken, "expired token")
+
+	other := userClaims(sub, time.Hour)
+	other.Issuer = "someone-else"
+	_, err = v.Verify(signHS256(t, "s3cret", other))
+	assert.ErrorIs(t, err, ErrInvalidToken, "wrong issuer")
+}
+
+func TestVerifyRS256FromJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	doc, _ := json.Marshal(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, doc, 0o600))
+
+	v, err := NewVerifier(Config{JWKSFile: path})
+	require.NoError(t, err)
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, userClaims(uuid.New(), time.Hour))
+	tok.Header["kid"] = "k1"
+	raw, err := tok.SignedString(key)
+	require.NoError(t, err)
+
+	_, err = v.Verify(raw)
+	assert.NoError(t, err)
+
+	// an HS256 token has no key when only RSA keys are configured
+	_, err = v.Verify(signHS256(t, "s3cret", userClaims(uuid.New(), time.Hour)))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewVerifierWithoutKeys(t *testing.T) {
+	_, err := NewVerifier(Config{})
+	assert.ErrorIs(t, err, ErrNoKeys)
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	v, err := NewVerifier(Config{HMACSecret: "s3cret"})
+	require.NoError(t, err)
+
+	sub := uuid.New()
+	r := gin.New()
+	r.Use(Middleware(v, nil))
+	r.GET("/me", func(c *gin.Context) {
+		p, _ := FromContext(c)
+		c.String(http.StatusOK, p.Subject.String())
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+signHS256(t, "s3cret", userClaims(sub, time.Hour)))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, sub.String(), w.Body.String())
+
+	notUUID := userClaims(sub, time.Hour)
+	notUUID.Subject = "alice"
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+signHS256(t, "s3cret", notUUID))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestPrincipalTenantClaim(t *testing.T) {
+	tenant := uuid.New()
+	c := &Claims{RegisteredClaims: userClaims(uuid.New(), time.Hour), TenantID: tenant.String()}
+
+	p, err := c.Principal()
+	require.NoError(t, err)
+	assert.Equal(t, tenant, p.Tenant)
+
+	c.TenantID = "acme"
+	_, err = c.Principal()
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+type tenantRoles map[uuid.UUID][]string
+
+func (r tenantRoles) UserRoles(tenantID, userID uuid.UUID) ([]string, error) {
+	return r[tenantID], nil
+}
+
+func TestResolveRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	acme, globex := uuid.New(), uuid.New()
+	roles := tenantRoles{acme: {RoleAdmin}, globex: {RoleStaff}}
+
+	run := func(p *Principal) []string {
+		r := gin.New()
+		r.Use(func(c *gin.Context) { c.Set(PrincipalKey, p) }, ResolveRoles(roles))
+		r.GET("/me", func(c *gin.Context) {})
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/me", nil))
+		return p.Roles
+	}
+
+	assert.Equal(t, []string{RoleAdmin}, run(&Principal{Subject: uuid.New(), Kind: PrincipalUser, Tenant: acme}))
+	assert.Equal(t, []string{RoleStaff}, run(&Principal{Subject: uuid.New(), Kind: PrincipalUser, Tenant: globex}), "grants are per tenant")
+	assert.Equal(t, []string{RoleAuthor}, run(&Principal{Subject: uuid.New(), Kind: PrincipalUser, Tenant: acme, Roles: []string{RoleAuthor}}), "token roles win")
+	assert.Empty(t, run(&Principal{Subject: uuid.New(), Kind: PrincipalAPIKey, Tenant: acme}), "keys have scopes, not roles")
+}
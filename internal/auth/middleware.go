@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PrincipalKey is the gin context key holding the authenticated *Principal.
+const PrincipalKey = "auth.principal"
+
+const (
+	PrincipalUser   = "user"
+	PrincipalAPIKey = "api_key"
+)
+
+// Principal is the authenticated caller of a request: a user with roles, or
+// a service API key with scopes. For API keys Subject is the key id. Tenant
+// is uuid.Nil when the credential does not name one, until
+// tenant.Middleware fills in the tenant the request was resolved to.
+type Principal struct {
+	Subject uuid.UUID
+	Kind    string
+	Roles   []string
+	Scopes  []string
+	Tenant  uuid.UUID
+}
+
+// Middleware rejects requests without a valid bearer token or API key and
+// stores the caller's Principal in the gin context. User roles come from the
+// token's roles claim; ResolveRoles fills them in for tokens without one.
+func Middleware(v *Verifier, keys APIKeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader(APIKeyHeader); key != "" && keys != nil {
+			p, err := authenticateAPIKey(keys, key, time.Now())
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+				return
+			}
+			c.Set(PrincipalKey, p)
+			c.Next()
+			return
+		}
+
+		header := c.GetHeader("Authorization")
+		raw, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || strings.TrimSpace(raw) == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+			return
+		}
+
+		claims, err := v.Verify(strings.TrimSpace(raw))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+
+		p, err := claims.Principal()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token subject"})
+			return
+		}
+
+		c.Set(PrincipalKey, p)
+		c.Next()
+	}
+}
+
+// ResolveRoles loads the roles of users whose token carries none from the
+// tenant's role table. Grants are per tenant, so it must run after
+// tenant.Middleware.
+func ResolveRoles(roles RoleStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := FromContext(c)
+		if !ok || p.Kind != PrincipalUser || len(p.Roles) > 0 {
+			c.Next()
+			return
+		}
+
+		var err error
+		p.Roles, err = roles.UserRoles(p.Tenant, p.Subject)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "cannot resolve roles"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// FromContext returns the Principal stored by Middleware.
+func FromContext(c *gin.Context) (*Principal, bool) {
+	v, ok := c.Get(PrincipalKey)
+	if !ok {
+		return nil, false
+	}
+	p, ok := v.(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedDenials struct {
+	entries []AccessDenial
+}
+
+func (r *recordedDenials) RecordAccessDenied(d AccessDenial) error {
+	r.entries = append(r.entries, d)
+	return nil
+}
+
+func TestPrincipalPermissions(t *testing.T) {
+	staff := &Principal{Subject: uuid.New()}
+	assert.True(t, staff.Can(PermEventsRead))
+	assert.False(t, staff.Can(PermEventsWrite))
+
+	author := &Principal{Subject: uuid.New(), Roles: []string{RoleAuthor}}
+	assert.True(t, author.Can(PermBroadcastTrigger))
+	assert.False(t, author.Can(PermEventsModerate))
+
+	moderator := &Principal{Subject: uuid.New(), Roles: []string{RoleModerator}}
+	assert.True(t, moderator.Can(PermEventsModerate))
+	assert.False(t, moderator.Can(PermBroadcastTrigger))
+
+	admin := &Principal{Subject: uuid.New(), Roles: []string{RoleAdmin}}
+	assert.True(t, admin.Can(PermBroadcastTrigger))
+	assert.False(t, admin.Can(PermEventsModerate), "only moderators moderate")
+}
+
+func TestGuardRequire(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	denials := &recordedDenials{}
+	g := NewGuard(denials)
+	caller := &Principal{Subject: uuid.New(), Roles: []string{RoleAuthor}}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set(PrincipalKey, caller) })
+	r.POST("/events/:id/moderate", g.Require(PermEventsModerate), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.POST("/events/:id/broadcast", g.Require(PermBroadcastTrigger), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/1/moderate", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"error":"forbidden","permission":"events:moderate","reason":"missing permission"}`, w.Body.String())
+
+	if assert.Len(t, denials.entries, 1) {
+		assert.Equal(t, caller.Subject, denials.entries[0].ActorID)
+		assert.Equal(t, "/events/:id/moderate", denials.entries[0].Route)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/1/broadcast", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, denials.entries, 1)
+}
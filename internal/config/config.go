@@ -2,10 +2,15 @@ package config
 
 import (
     "os"
+    "strconv"
+    "strings"
+    "time"
+
     "github.com/joho/godotenv"
 )
 
 type Config struct {
+    Env  string // development | staging | production
     Port string
 
     DBHost string
@@ -13,12 +18,90 @@ type Config struct {
     DBUser string
     DBPass string
     DBName string
+
+    DBSSLMode     string // disable | require | verify-ca | verify-full
+    DBSSLRootCert string // CA bundle for verify-ca / verify-full
+    DBSSLCert     string // optional client certificate
+    DBSSLKey      string
+
+    TLSCertFile string // serve HTTPS when both are set
+    TLSKeyFile  string
+
+    CORSAllowOrigins     []string
+    CORSAllowMethods     []string
+    CORSAllowCredentials bool
+    CORSMaxAge           time.Duration
+
+    HSTSMaxAge            time.Duration // 0 disables Strict-Transport-Security
+    ContentSecurityPolicy string
+
+    DefaultTenantID string // tenant for requests whose token and host name none
+
+    StorageBackend   string // local | s3, where attachment content is kept
+    StorageLocalDir  string
+    S3Bucket         string
+    S3Region         string
+    S3Endpoint       string // S3-compatible services such as MinIO
+    S3ForcePathStyle bool
+
+    PublicBaseURL string // e.g. https://events.example.com; push notifications carry cover images only when set
+
+    AttachmentAllowedTypes  []string // sniffed MIME types; empty keeps the built-in list
+    AttachmentMaxFileBytes  int64
+    AttachmentMaxEventBytes int64
+    AttachmentAuthorQuota   int64         // bytes per author; 0 is unlimited
+    AttachmentUnlinkedTTL   time.Duration // uploads never linked to an event are then deleted; 0 keeps them
+
+    AttachmentLinkTTL        time.Duration // signed download links in API responses
+    AttachmentMessageLinkTTL time.Duration // signed links in emails, Teams cards and push notifications
+
+    ScannerBackend string // clamd | fake (development only)
+    ClamdNetwork   string // tcp | unix
+    ClamdAddress   string
+    ScanTimeout    time.Duration
+
+    JWTSecret        string // HS256
+    JWTPublicKeyFile string // RS256 PEM
+    JWTJWKSFile      string // local JWKS
+    JWTIssuer        string
+    JWTAudience      string
+
+    RateLimitStore       string // memory | postgres
+    RateLimitIP          string // count/unit[:burst], per client IP before authentication
+    RateLimitDefault     string // count/unit[:burst], per caller across /api/v1
+    RateLimitEventsWrite string // creating and editing events
+    RateLimitBroadcast   string // manual broadcasts and recalls
+
+    RetryPolicyFCM   string // attempts/base delay/max delay for failed deliveries
+    RetryPolicyEmail string
+    RetryPolicyTeams string
+
+    BroadcastLeaseTTL     time.Duration // how long a claimed job may go without a heartbeat before it is reaped
+    BroadcastPollInterval time.Duration
+    BroadcastBatchSize    int // most jobs claimed per channel at once
+
+    BroadcastConcurrencyFCM   int // jobs delivered in parallel per channel
+    BroadcastConcurrencyEmail int
+    BroadcastConcurrencyTeams int
+    SESSendConcurrency        int // SES requests in flight per email job
 }
 
 func Load() *Config {
     godotenv.Load()
 
+    env := getEnv("APP_ENV", "development")
+    prod := env == "production"
+
+    // development talks to the local React dev servers over plain HTTP
+    origins, sslMode, hsts := "http://localhost:3000,http://localhost:5173", "disable", "0"
+    if prod {
+        origins, sslMode, hsts = "", "require", "8760h"
+    } else if env == "staging" {
+        origins, sslMode, hsts = "", "require", "24h"
+    }
+
     return &Config{
+        Env:  env,
         Port: os.Getenv("PORT"),
 
         DBHost: os.Getenv("DB_HOST"),
@@ -26,5 +109,129 @@ func Load() *Config {
         DBUser: os.Getenv("DB_USER"),
         DBPass: os.Getenv("DB_PASS"),
         DBName: os.Getenv("DB_NAME"),
+
+        DBSSLMode:     getEnv("DB_SSLMODE", sslMode),
+        DBSSLRootCert: os.Getenv("DB_SSLROOTCERT"),
+        DBSSLCert:     os.Getenv("DB_SSLCERT"),
+        DBSSLKey:      os.Getenv("DB_SSLKEY"),
+
+        TLSCertFile: os.Getenv("TLS_CERT_FILE"),
+        TLSKeyFile:  os.Getenv("TLS_KEY_FILE"),
+
+        CORSAllowOrigins:     getList("CORS_ALLOW_ORIGINS", origins),
+        CORSAllowMethods:     getList("CORS_ALLOW_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS"),
+        CORSAllowCredentials: getBool("CORS_ALLOW_CREDENTIALS", true),
+        CORSMaxAge:           getDuration("CORS_MAX_AGE", "12h"),
+
+        HSTSMaxAge:            getDuration("HSTS_MAX_AGE", hsts),
+        ContentSecurityPolicy: getEnv("CONTENT_SECURITY_POLICY", "default-src 'none'; frame-ancestors 'none'; base-uri 'none'"),
+
+        DefaultTenantID: os.Getenv("DEFAULT_TENANT_ID"),
+
+        StorageBackend:   getEnv("STORAGE_BACKEND", "local"),
+        StorageLocalDir:  getEnv("STORAGE_LOCAL_DIR", "./data/attachments"),
+        S3Bucket:         os.Getenv("S3_BUCKET"),
+        S3Region:         os.Getenv("S3_REGION"),
+        S3Endpoint:       os.Getenv("S3_ENDPOINT"),
+        S3ForcePathStyle: getBool("S3_FORCE_PATH_STYLE", false),
+
+        PublicBaseURL: strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/"),
+
+        AttachmentAllowedTypes:  getList("ATTACHMENT_ALLOWED_TYPES", ""),
+        AttachmentMaxFileBytes:  getInt64("ATTACHMENT_MAX_FILE_BYTES", 25<<20),
+        AttachmentMaxEventBytes: getInt64("ATTACHMENT_MAX_EVENT_BYTES", 50<<20),
+        AttachmentAuthorQuota:   getInt64("ATTACHMENT_AUTHOR_QUOTA_BYTES", 1<<30),
+        AttachmentUnlinkedTTL:   getDuration("ATTACHMENT_UNLINKED_TTL", "24h"),
+
+        AttachmentLinkTTL:        getDuration("ATTACHMENT_LINK_TTL", "1h"),
+        AttachmentMessageLinkTTL: getDuration("ATTACHMENT_MESSAGE_LINK_TTL", "168h"),
+
+        ScannerBackend: getEnv("SCANNER_BACKEND", "clamd"),
+        ClamdNetwork:   getEnv("CLAMD_NETWORK", "tcp"),
+        ClamdAddress:   getEnv("CLAMD_ADDRESS", "localhost:3310"),
+        ScanTimeout:    getDuration("SCAN_TIMEOUT", "2m"),
+
+        JWTSecret:        os.Getenv("JWT_SECRET"),
+        JWTPublicKeyFile: os.Getenv("JWT_PUBLIC_KEY_FILE"),
+        JWTJWKSFile:      os.Getenv("JWT_JWKS_FILE"),
+        JWTIssuer:        os.Getenv("JWT_ISSUER"),
+        JWTAudience:      os.Getenv("JWT_AUDIENCE"),
+
+        RateLimitStore:       getEnv("RATE_LIMIT_STORE", "memory"),
+        RateLimitIP:          getEnv("RATE_LIMIT_IP", "1200/m"),
+        RateLimitDefault:     getEnv("RATE_LIMIT_DEFAULT", "300/m"),
+        RateLimitEventsWrite: getEnv("RATE_LIMIT_EVENTS_WRITE", "30/m"),
+        RateLimitBroadcast:   getEnv("RATE_LIMIT_BROADCAST", "5/m"),
+
+        RetryPolicyFCM:   getEnv("RETRY_POLICY_FCM", "5/30s/30m"),
+        RetryPolicyEmail: getEnv("RETRY_POLICY_EMAIL", "6/1m/2h"),
+        RetryPolicyTeams: getEnv("RETRY_POLICY_TEAMS", "5/30s/30m"),
+
+        BroadcastLeaseTTL:     getPositiveDuration("BROADCAST_LEASE_TTL", "2m"),
+        BroadcastPollInterval: getPositiveDuration("BROADCAST_POLL_INTERVAL", "5s"),
+        BroadcastBatchSize:    getInt("BROADCAST_BATCH_SIZE", 10),
+
+        BroadcastConcurrencyFCM:   getInt("BROADCAST_CONCURRENCY_FCM", 4),
+        BroadcastConcurrencyEmail: getInt("BROADCAST_CONCURRENCY_EMAIL", 2),
+        BroadcastConcurrencyTeams: getInt("BROADCAST_CONCURRENCY_TEAMS", 2),
+        SESSendConcurrency:        getInt("SES_SEND_CONCURRENCY", 5),
+    }
+}
+
+func getEnv(key, fallback string) string {
+    if v := os.Getenv(key); v != "" {
+        return v
+    }
+    return fallback
+}
+
+// getList reads a comma-separated list, dropping empty items.
+func getList(key, fallback string) []string {
+    var out []string
+    for _, item := range strings.Split(getEnv(key, fallback), ",") {
+        if item = strings.TrimSpace(item); item != "" {
+            out = append(out, item)
+        }
+    }
+    return out
+}
+
+func getBool(key string, fallback bool) bool {
+    if b, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+        return b
+    }
+    return fallback
+}
+
+func getInt64(key string, fallback int64) int64 {
+    if n, err := strconv.ParseInt(os.Getenv(key), 10, 64); err == nil {
+        return n
+    }
+    return fallback
+}
+
+func getInt(key string, fallback int) int {
+    if n, err := strconv.Atoi(os.Getenv(key)); err == nil && n > 0 {
+        return n
+    }
+    return fallback
+}
+
+// getDuration accepts Go durations such as "12h"; bad values fall back.
+func getDuration(key, fallback string) time.Duration {
+    if d, err := time.ParseDuration(os.Getenv(key)); err == nil {
+        return d
+    }
+    d, _ := time.ParseDuration(fallback)
+    return d
+}
+
+// getPositiveDuration is getDuration for settings where zero or less makes
+// no sense, such as poll intervals; those fall back too.
+func getPositiveDuration(key, fallback string) time.Duration {
+    if d := getDuration(key, fallback); d > 0 {
+        return d
     }
+    d, _ := time.ParseDuration(fallback)
+    return d
 }
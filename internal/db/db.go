@@ -2,6 +2,9 @@ package db
 
 import (
 	"events-service/internal/config"
+	"events-service/internal/tenant"
+	"fmt"
+	"strings"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -12,13 +15,52 @@ func InitDB(cfg *config.Config) *gorm.DB {
         " user=" + cfg.DBUser +
         " password=" + cfg.DBPass +
         " dbname=" + cfg.DBName +
-        " port=" + cfg.DBPort +
-        " sslmode=disable"
+        " port=" + cfg.DBPort
+
+    ssl, err := sslParams(cfg)
+    if err != nil {
+        panic(err)
+    }
+    dsn += ssl
 
     db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
     if err != nil {
         panic(err)
     }
 
+    // scope tenant-owned tables to the tenant in each query's context
+    if err := tenant.Register(db); err != nil {
+        panic(err)
+    }
+
     return db
 }
+
+// sslParams builds the libpq SSL settings. verify-ca and verify-full need a
+// CA bundle to check the server certificate against.
+func sslParams(cfg *config.Config) (string, error) {
+    mode := strings.ToLower(cfg.DBSSLMode)
+    switch mode {
+    case "":
+        mode = "disable"
+    case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+    default:
+        return "", fmt.Errorf("db: unknown DB_SSLMODE %q", cfg.DBSSLMode)
+    }
+
+    if (mode == "verify-ca" || mode == "verify-full") && cfg.DBSSLRootCert == "" {
+        return "", fmt.Errorf("db: DB_SSLMODE=%s needs DB_SSLROOTCERT", mode)
+    }
+    if (cfg.DBSSLCert == "") != (cfg.DBSSLKey == "") {
+        return "", fmt.Errorf("db: DB_SSLCERT and DB_SSLKEY must be set together")
+    }
+
+    params := " sslmode=" + mode
+    if cfg.DBSSLRootCert != "" {
+        params += " sslrootcert=" + cfg.DBSSLRootCert
+    }
+    if cfg.DBSSLCert != "" {
+        params += " sslcert=" + cfg.DBSSLCert + " sslkey=" + cfg.DBSSLKey
+    }
+    return params, nil
+}
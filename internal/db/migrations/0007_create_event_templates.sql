@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS event_templates (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    title TEXT NOT NULL,
+    summary TEXT,
+    body TEXT NOT NULL,
+    default_tags JSONB NOT NULL DEFAULT '[]',
+    variables JSONB NOT NULL DEFAULT '[]',
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_event_templates_name ON event_templates(name);
@@ -0,0 +1,5 @@
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_status_check;
+ALTER TABLE events ADD CONSTRAINT events_status_check
+    CHECK (status IN ('draft','pending','approved','rejected','archived','retracted'));
+
+CREATE INDEX IF NOT EXISTS idx_broadcast_queue_event_status ON broadcast_queue(event_id, status);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id UUID NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('staff','author','moderator','admin')),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (user_id, role)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS access_audit (
+    id SERIAL PRIMARY KEY,
+    actor_id UUID,
+    method TEXT NOT NULL,
+    route TEXT NOT NULL,
+    permission TEXT NOT NULL,
+    reason TEXT,
+    client_ip TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_access_audit_actor_id ON access_audit(actor_id);
+CREATE INDEX idx_access_audit_created_at ON access_audit(created_at);
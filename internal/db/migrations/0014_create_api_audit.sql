@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS api_audit (
+    id BIGSERIAL PRIMARY KEY,
+    request_id TEXT NOT NULL,
+    actor_id UUID,
+    actor_kind TEXT,
+    method TEXT NOT NULL,
+    route TEXT NOT NULL,
+    path TEXT NOT NULL,
+    target_type TEXT,
+    target_id TEXT,
+    status_code INT NOT NULL,
+    client_ip TEXT,
+    request_digest TEXT,
+    before_digest TEXT,
+    after_digest TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_api_audit_actor_id ON api_audit(actor_id);
+CREATE INDEX idx_api_audit_target ON api_audit(target_type, target_id);
+CREATE INDEX idx_api_audit_created_at ON api_audit(created_at);
+
+-- append-only: rows can be inserted but never changed or removed
+CREATE OR REPLACE FUNCTION api_audit_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'api_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_api_audit_append_only
+    BEFORE UPDATE OR DELETE ON api_audit
+    FOR EACH ROW EXECUTE FUNCTION api_audit_append_only();
@@ -0,0 +1,8 @@
+-- Each row stores the hash of the previous row, so any later change or
+-- removal shows up as a broken link. Rows written before this migration
+-- have no hash and are reported as unchained.
+ALTER TABLE publish_audit ADD COLUMN IF NOT EXISTS prev_hash TEXT;
+ALTER TABLE publish_audit ADD COLUMN IF NOT EXISTS hash TEXT;
+
+-- Deleting an event must not delete its audit history out of the chain.
+ALTER TABLE publish_audit DROP CONSTRAINT IF EXISTS publish_audit_event_id_fkey;
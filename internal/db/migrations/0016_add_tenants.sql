@@ -0,0 +1,54 @@
+-- One deployment serves several client organisations. Every tenant-owned
+-- row carries tenant_id; rows that existed before belong to the default
+-- tenant.
+CREATE TABLE IF NOT EXISTS tenants (
+    id UUID PRIMARY KEY,
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    host TEXT UNIQUE,                       -- e.g. acme.events.example.com
+    ses_source TEXT NOT NULL DEFAULT '',    -- From address for email broadcasts
+    fcm_credentials_file TEXT NOT NULL DEFAULT '',
+    fcm_topic TEXT NOT NULL DEFAULT 'events',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO tenants (id, slug, name, ses_source, fcm_credentials_file)
+VALUES ('00000000-0000-0000-0000-000000000001', 'eyepax', 'Eyepax', 'yasela.d@eyepax.com', 'fcm.json')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE events SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE events ALTER COLUMN tenant_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_events_tenant_created ON events(tenant_id, created_at DESC);
+
+ALTER TABLE broadcast_queue ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE broadcast_queue SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE broadcast_queue ALTER COLUMN tenant_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_broadcast_queue_tenant ON broadcast_queue(tenant_id, status);
+
+-- tenant_id is not part of the row hash, so backfilling keeps the chain valid
+ALTER TABLE publish_audit ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE publish_audit SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE publish_audit ALTER COLUMN tenant_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_publish_audit_tenant ON publish_audit(tenant_id, event_id);
+
+ALTER TABLE global_tags ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE global_tags SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE global_tags ALTER COLUMN tenant_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_global_tags_tenant ON global_tags(tenant_id, tag);
+
+-- feed_meta goes from a single id=1 row to one row per tenant
+ALTER TABLE feed_meta ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE feed_meta SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE id = 1 AND tenant_id IS NULL;
+DELETE FROM feed_meta WHERE tenant_id IS NULL;
+ALTER TABLE feed_meta ALTER COLUMN tenant_id SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feed_meta_tenant ON feed_meta(tenant_id);
+CREATE SEQUENCE IF NOT EXISTS feed_meta_id_seq OWNED BY feed_meta.id;
+SELECT setval('feed_meta_id_seq', GREATEST((SELECT MAX(id) FROM feed_meta), 1));
+ALTER TABLE feed_meta ALTER COLUMN id SET DEFAULT nextval('feed_meta_id_seq');
+
+-- staff directory, so broadcasts only reach the tenant's own people
+ALTER TABLE app_users ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE app_users SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE app_users ALTER COLUMN tenant_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_app_users_tenant ON app_users(tenant_id);
@@ -0,0 +1,11 @@
+-- Events name who they are for; '{}' is everyone in the tenant. Lists are
+-- stored lower-cased so they compare directly with the staff directory.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS audience JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- staff directory attributes the audience is matched against
+ALTER TABLE app_users ADD COLUMN IF NOT EXISTS department TEXT NOT NULL DEFAULT '';
+ALTER TABLE app_users ADD COLUMN IF NOT EXISTS location TEXT NOT NULL DEFAULT '';
+ALTER TABLE app_users ADD COLUMN IF NOT EXISTS job_role TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_app_users_tenant_department ON app_users(tenant_id, LOWER(department));
+CREATE INDEX IF NOT EXISTS idx_app_users_tenant_location ON app_users(tenant_id, LOWER(location));
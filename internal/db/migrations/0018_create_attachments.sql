@@ -0,0 +1,17 @@
+-- Uploaded files; the bytes are in object storage under storage_key.
+-- Unlinked uploads have no event_id until an event is saved with them.
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    event_id UUID REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    mime_type TEXT NOT NULL,
+    checksum TEXT NOT NULL,
+    storage_key TEXT NOT NULL,
+    uploaded_by UUID NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_tenant ON attachments(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_event ON attachments(event_id);
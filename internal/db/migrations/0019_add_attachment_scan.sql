@@ -0,0 +1,8 @@
+-- Uploads are quarantined until the malware scanner has passed them.
+-- Existing uploads were never scanned and are picked up by the scan worker.
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS scan_status TEXT NOT NULL DEFAULT 'pending'
+    CHECK (scan_status IN ('pending', 'clean', 'infected'));
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS scan_signature TEXT NOT NULL DEFAULT '';
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_attachments_scan_pending ON attachments(created_at) WHERE scan_status = 'pending';
@@ -0,0 +1,6 @@
+-- Image uploads carry resized renditions; an event may pick one of its
+-- image attachments as its cover.
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS variants JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS cover_attachment_id UUID
+    REFERENCES attachments(id) ON DELETE SET NULL;
@@ -0,0 +1,12 @@
+-- HMAC secrets for signed, expiring attachment links. Retired keys are kept
+-- until expires_at so links they signed keep working until they expire.
+CREATE TABLE IF NOT EXISTS signing_keys (
+    id TEXT PRIMARY KEY,
+    secret BYTEA NOT NULL,
+    created_by UUID,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    retired_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_signing_keys_active ON signing_keys(created_at) WHERE retired_at IS NULL;
@@ -0,0 +1,5 @@
+-- Failed broadcast jobs wait for their retry time instead of running on
+-- the next poll.
+ALTER TABLE broadcast_queue ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_broadcast_queue_due ON broadcast_queue(status, next_attempt_at);
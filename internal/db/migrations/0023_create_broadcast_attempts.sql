@@ -0,0 +1,12 @@
+-- Error history of broadcast jobs, for the admin dead-letter view.
+CREATE TABLE IF NOT EXISTS broadcast_attempts (
+    id SERIAL PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    job_id INT NOT NULL REFERENCES broadcast_queue(id) ON DELETE CASCADE,
+    attempt INT NOT NULL,
+    error TEXT NOT NULL,
+    retryable BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_broadcast_attempts_job ON broadcast_attempts(job_id, attempt);
@@ -0,0 +1,10 @@
+-- Workers lease the jobs they claim; jobs whose lease runs out are
+-- returned to the queue.
+ALTER TABLE broadcast_queue ADD COLUMN IF NOT EXISTS lease_owner TEXT;
+ALTER TABLE broadcast_queue ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;
+
+-- jobs stuck in processing before leases existed get reaped on first run
+UPDATE broadcast_queue SET lease_expires_at = updated_at
+WHERE status = 'processing' AND lease_expires_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_broadcast_queue_lease ON broadcast_queue(status, lease_expires_at);
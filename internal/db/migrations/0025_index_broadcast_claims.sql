@@ -0,0 +1,2 @@
+-- Each channel claims its own pending jobs.
+CREATE INDEX IF NOT EXISTS idx_broadcast_queue_claim ON broadcast_queue(channel, status, created_at);
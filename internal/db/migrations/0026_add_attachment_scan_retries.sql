@@ -0,0 +1,6 @@
+-- Failed scans back off between retries and give up with scan_status
+-- 'scan_error' after a bounded number of attempts.
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS scan_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS next_scan_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_attachments_pending_scans ON attachments(scan_status, next_scan_at);
@@ -0,0 +1,6 @@
+-- Image renditions are made by the scan worker once an upload scans clean;
+-- their bytes count toward the uploader's quota.
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS needs_variants BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS variant_bytes BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_attachments_needs_variants ON attachments(created_at) WHERE needs_variants;
@@ -0,0 +1,28 @@
+-- Templates, API keys, role grants and the API audit log belong to a tenant
+-- too; existing rows go to the default tenant.
+ALTER TABLE event_templates ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE event_templates SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE event_templates ALTER COLUMN tenant_id SET NOT NULL;
+DROP INDEX IF EXISTS idx_event_templates_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_templates_tenant_name ON event_templates(tenant_id, name);
+
+-- prefixes stay globally unique: a key is looked up before its tenant is known
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE api_keys SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE api_keys ALTER COLUMN tenant_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_api_keys_tenant ON api_keys(tenant_id);
+
+-- a user may hold different roles in different tenants
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE user_roles SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE user_roles ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE user_roles DROP CONSTRAINT IF EXISTS user_roles_pkey;
+ALTER TABLE user_roles ADD PRIMARY KEY (tenant_id, user_id, role);
+
+-- the backfill is the one update the append-only log ever takes
+ALTER TABLE api_audit DISABLE TRIGGER trg_api_audit_append_only;
+ALTER TABLE api_audit ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE api_audit SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE api_audit ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE api_audit ENABLE TRIGGER trg_api_audit_append_only;
+CREATE INDEX IF NOT EXISTS idx_api_audit_tenant_created ON api_audit(tenant_id, created_at);
@@ -0,0 +1,7 @@
+-- Each tenant signs links with its own keys; existing keys go to the
+-- default tenant. Links signed before this carry no tenant and stop
+-- verifying.
+ALTER TABLE signing_keys ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE signing_keys SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE signing_keys ALTER COLUMN tenant_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_signing_keys_tenant_created ON signing_keys(tenant_id, created_at);
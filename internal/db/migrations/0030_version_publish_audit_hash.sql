@@ -0,0 +1,4 @@
+-- Rows chained from now on also hash their tenant (version 2). Rows chained
+-- before tenants existed were given one afterwards that their hash does not
+-- cover; they keep version 1 and verify as they were written.
+ALTER TABLE publish_audit ADD COLUMN IF NOT EXISTS hash_version SMALLINT NOT NULL DEFAULT 1;
@@ -0,0 +1,40 @@
+-- The newest hash of the publish_audit chain and how many rows it links,
+-- kept outside the table: deleting the newest rows leaves a chain that is
+-- valid on its own but shorter than its head.
+CREATE TABLE IF NOT EXISTS publish_audit_head (
+    id SMALLINT PRIMARY KEY CHECK (id = 1),
+    hash TEXT NOT NULL,
+    chained BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO publish_audit_head (id, hash, chained)
+SELECT 1,
+       COALESCE((SELECT hash FROM publish_audit WHERE hash IS NOT NULL AND hash <> '' ORDER BY id DESC LIMIT 1), ''),
+       (SELECT COUNT(*) FROM publish_audit WHERE hash IS NOT NULL AND hash <> '')
+ON CONFLICT (id) DO NOTHING;
+
+-- append-only, like api_audit: rows can be inserted but never changed or removed
+CREATE OR REPLACE FUNCTION publish_audit_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'publish_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_publish_audit_append_only
+    BEFORE UPDATE OR DELETE ON publish_audit
+    FOR EACH ROW EXECUTE FUNCTION publish_audit_append_only();
+
+-- the head only moves forward, one appended row at a time
+CREATE OR REPLACE FUNCTION publish_audit_head_forward_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' OR NEW.chained <> OLD.chained + 1 THEN
+        RAISE EXCEPTION 'publish_audit_head only moves forward';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_publish_audit_head_forward_only
+    BEFORE UPDATE OR DELETE ON publish_audit_head
+    FOR EACH ROW EXECUTE FUNCTION publish_audit_head_forward_only();
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"events-service/internal/events/repository"
+	"events-service/internal/events/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ListBroadcasts lists broadcast jobs of the tenant, e.g. ?status=failed for
+// the dead letters.
+func (h *EventHandler) ListBroadcasts(c *gin.Context) {
+	var query BroadcastListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query params"})
+		return
+	}
+
+	filter, err := broadcastFilter(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.Size <= 0 || query.Size > 200 {
+		query.Size = 50
+	}
+
+	jobs, total, err := h.svc(c).ListBroadcasts(filter, query.Page, query.Size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot load broadcast jobs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, BroadcastListResponse{
+		Page:  query.Page,
+		Size:  query.Size,
+		Total: total,
+		Jobs:  jobs,
+	})
+}
+
+// GetBroadcast returns a job with its payload and every failed attempt.
+func (h *EventHandler) GetBroadcast(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job id"})
+		return
+	}
+
+	detail, err := h.svc(c).GetBroadcast(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "broadcast job not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot load broadcast job"})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+func (h *EventHandler) RetryBroadcast(c *gin.Context) {
+	h.broadcastAction(c, repository.BroadcastRetry)
+}
+
+func (h *EventHandler) CancelBroadcast(c *gin.Context) {
+	h.broadcastAction(c, repository.BroadcastCancel)
+}
+
+func (h *EventHandler) PurgeBroadcast(c *gin.Context) {
+	h.broadcastAction(c, repository.BroadcastPurge)
+}
+
+func (h *EventHandler) broadcastAction(c *gin.Context, action string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job id"})
+		return
+	}
+
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	err = h.svc(c).ApplyBroadcastAction(id, action, admin)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "broadcast job not found"})
+	case errors.Is(err, repository.ErrBroadcastActionNotAllowed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to " + action + " broadcast job"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"id": id, "action": action})
+	}
+}
+
+// BulkBroadcasts applies one action to the listed jobs or to every job
+// matching a filter. Failures are reported per job.
+func (h *EventHandler) BulkBroadcasts(c *gin.Context) {
+	svc := h.svc(c)
+	var dto BulkBroadcastsDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch dto.Action {
+	case repository.BroadcastRetry, repository.BroadcastCancel, repository.BroadcastPurge:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action must be one of retry, cancel, purge"})
+		return
+	}
+
+	if (len(dto.IDs) == 0) == (dto.Filter == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "either ids or filter is required"})
+		return
+	}
+
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	ids := dto.IDs
+	if dto.Filter != nil {
+		filter, err := broadcastFilter(*dto.Filter)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if ids, err = svc.BroadcastIDs(filter); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot load broadcast jobs"})
+			return
+		}
+		if len(ids) > service.MaxBulkBroadcasts {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "filter matches more than " + strconv.Itoa(service.MaxBulkBroadcasts) + " jobs, narrow it down",
+			})
+			return
+		}
+	}
+
+	resp := BulkBroadcastsResponse{
+		Action:  dto.Action,
+		Results: make([]BulkBroadcastResult, 0, len(ids)),
+	}
+
+	for _, id := range ids {
+		result := BulkBroadcastResult{ID: id}
+
+		err := svc.ApplyBroadcastAction(id, dto.Action, admin)
+		switch {
+		case err == nil:
+			result.Success = true
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			result.Error = "broadcast job not found"
+		case errors.Is(err, repository.ErrBroadcastActionNotAllowed):
+			result.Error = err.Error()
+		default:
+			result.Error = "unable to " + dto.Action + " broadcast job"
+		}
+
+		if result.Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func broadcastFilter(query BroadcastListQuery) (repository.BroadcastFilter, error) {
+	filter := repository.BroadcastFilter{
+		Status:  query.Status,
+		Channel: query.Channel,
+	}
+
+	if query.EventID != "" {
+		id, err := uuid.Parse(query.EventID)
+		if err != nil {
+			return filter, errors.New("invalid event_id")
+		}
+		filter.EventID = &id
+	}
+
+	for _, bound := range []struct {
+		raw  string
+		dest **time.Time
+		name string
+	}{{query.From, &filter.From, "from"}, {query.To, &filter.To, "to"}} {
+		if bound.raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.raw)
+		if err != nil {
+			return filter, errors.New("invalid " + bound.name)
+		}
+		*bound.dest = &t
+	}
+
+	return filter, nil
+}
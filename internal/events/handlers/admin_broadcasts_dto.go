@@ -0,0 +1,40 @@
+package handlers
+
+import "events-service/internal/events/models"
+
+type BroadcastListQuery struct {
+	Status  string `form:"status" json:"status"` // pending | processing | sent | failed | cancelled
+	Channel string `form:"channel" json:"channel"`
+	EventID string `form:"event_id" json:"event_id"`
+	From    string `form:"from" json:"from"` // RFC3339, inclusive, on created_at
+	To      string `form:"to" json:"to"`     // RFC3339, exclusive
+	Page    int    `form:"page" json:"page"`
+	Size    int    `form:"size" json:"size"`
+}
+
+type BroadcastListResponse struct {
+	Page  int                     `json:"page"`
+	Size  int                     `json:"size"`
+	Total int64                   `json:"total"`
+	Jobs  []models.BroadcastQueue `json:"jobs"`
+}
+
+// BulkBroadcastsDTO names jobs either by id or by a filter, not both.
+type BulkBroadcastsDTO struct {
+	Action string              `json:"action" binding:"required"` // retry | cancel | purge
+	IDs    []int               `json:"ids" binding:"max=500"`
+	Filter *BroadcastListQuery `json:"filter"`
+}
+
+type BulkBroadcastResult struct {
+	ID      int    `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+type BulkBroadcastsResponse struct {
+	Action    string                `json:"action"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []BulkBroadcastResult `json:"results"`
+}
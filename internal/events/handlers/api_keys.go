@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"events-service/internal/events/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (h *EventHandler) CreateAPIKey(c *gin.Context) {
+	svc := h.svc(c)
+	var dto CreateAPIKeyDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, dto.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expires_at"})
+		return
+	}
+
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	key, plaintext, err := svc.IssueAPIKey(dto.Name, dto.Scopes, expiresAt, admin)
+	if errors.Is(err, service.ErrInvalidAPIKeyRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to create api key"})
+		return
+	}
+
+	// the plaintext key is only ever returned here
+	c.JSON(http.StatusCreated, gin.H{"api_key": key, "key": plaintext})
+}
+
+func (h *EventHandler) ListAPIKeys(c *gin.Context) {
+	svc := h.svc(c)
+	keys, err := svc.ListAPIKeys()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot load api keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+}
+
+func (h *EventHandler) RevokeAPIKey(c *gin.Context) {
+	svc := h.svc(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = svc.RevokeAPIKey(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "api key not found or already revoked"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to revoke api key"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "api key revoked"})
+}
@@ -0,0 +1,7 @@
+package handlers
+
+type CreateAPIKeyDTO struct {
+	Name      string   `json:"name" binding:"required"`
+	Scopes    []string `json:"scopes" binding:"required"`     // events:write | broadcast:trigger | tags:read
+	ExpiresAt string   `json:"expires_at" binding:"required"` // RFC3339
+}
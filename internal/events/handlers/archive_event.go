@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (h *EventHandler) ArchiveEvent(c *gin.Context) {
+	svc := h.svc(c)
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	err = svc.ArchiveEvent(eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to archive event"})
+		return
+	}
+
+	// ETag bump
+	_ = svc.IncrementFeedVersion()
+
+	c.JSON(http.StatusOK, gin.H{"message": "event archived"})
+}
+
+func (h *EventHandler) DeleteEvent(c *gin.Context) {
+	svc := h.svc(c)
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	err = svc.DeleteEvent(eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to delete event"})
+		return
+	}
+
+	// ETag bump
+	_ = svc.IncrementFeedVersion()
+
+	c.JSON(http.StatusOK, gin.H{"message": "event deleted"})
+}
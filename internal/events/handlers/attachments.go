@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/service"
+	"events-service/internal/storage"
+	"events-service/internal/urlsign"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// multipartOverhead allows for the boundaries and part headers around an
+// uploaded file.
+const multipartOverhead = 64 << 10
+
+// UploadAttachment stores the multipart "file" field as an unlinked upload.
+// Its id is then sent in an event's attachments list.
+func (h *EventHandler) UploadAttachment(c *gin.Context) {
+	svc := h.svc(c)
+	if max := svc.Attachments.MaxFileBytes; max > 0 {
+		// room for the multipart framing around the file
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, max+multipartOverhead)
+	}
+
+	fh, err := c.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeAttachmentError(c, &service.AttachmentError{
+			Code:    service.CodeFileTooLarge,
+			Message: fmt.Sprintf("files may be at most %d bytes", svc.Attachments.MaxFileBytes),
+			Details: map[string]any{"max_bytes": svc.Attachments.MaxFileBytes},
+		})
+		return
+	}
+	if err != nil {
+		writeAttachmentError(c, &service.AttachmentError{
+			Code:    service.CodeFileRequired,
+			Message: "multipart field \"file\" is required",
+		})
+		return
+	}
+
+	uploader, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot read upload"})
+		return
+	}
+	defer f.Close()
+
+	a, err := svc.UploadAttachment(c.Request.Context(), fh.Filename, f, fh.Size, uploader)
+	if writeAttachmentError(c, err) {
+		return
+	}
+	if errors.Is(err, service.ErrStorageNotConfigured) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to store attachment"})
+		return
+	}
+
+	links, err := svc.LinkSigner(svc.Links.TTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot sign links"})
+		return
+	}
+	a.URL = links.Attachment(a, "")
+
+	c.JSON(http.StatusCreated, a)
+}
+
+// DownloadAttachment streams an attachment, or with ?variant= one of an
+// image's renditions, through a signed link to callers who can see its
+// event. Unlinked uploads are only visible to their uploader.
+func (h *EventHandler) DownloadAttachment(c *gin.Context) {
+	svc := h.svc(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if _, ok := verifyLink(c, svc); !ok {
+		return
+	}
+
+	a, err := svc.GetAttachment(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "attachment not found"})
+		return
+	}
+
+	visible, err := canSeeAttachment(c, svc, a)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot resolve viewer"})
+		return
+	}
+	if !visible {
+		c.JSON(http.StatusNotFound, gin.H{"error": "attachment not found"})
+		return
+	}
+	if a.ScanStatus != models.ScanClean {
+		writeAttachmentError(c, &service.AttachmentError{
+			Code:    service.CodeAttachmentQuarantined,
+			Message: "attachment is quarantined until it passes the malware scan",
+			Details: map[string]any{"id": a.ID, "scan_status": a.ScanStatus},
+		})
+		return
+	}
+
+	if name := c.Query("variant"); name != "" {
+		writeVariant(c, svc, a, name, "private, max-age=3600")
+		return
+	}
+
+	rc, err := svc.OpenAttachment(c.Request.Context(), a)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "attachment content missing"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot read attachment"})
+		return
+	}
+	defer rc.Close()
+
+	c.DataFromReader(http.StatusOK, a.Size, a.MimeType, rc, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": a.Name}),
+		"ETag":                strconv.Quote(a.Checksum),
+	})
+}
+
+// DeleteAttachment deletes one of the caller's unlinked uploads, with its
+// stored content. Attachments on an event are dropped from it first.
+func (h *EventHandler) DeleteAttachment(c *gin.Context) {
+	svc := h.svc(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	actor, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	err = svc.DeleteAttachment(c.Request.Context(), id, actor)
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, service.ErrAttachmentUnavailable) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "attachment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to delete attachment"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// verifyLink refuses requests whose link is unsigned, tampered with,
+// expired or signed for another tenant, and returns the link's tenant. A
+// valid link still only works for callers allowed to see the content.
+func verifyLink(c *gin.Context, svc *service.EventService) (uuid.UUID, bool) {
+	tenantID, err := svc.VerifyLink(c.Request.URL.Path, c.Request.URL.Query())
+	switch {
+	case err == nil:
+		return tenantID, true
+	case errors.Is(err, urlsign.ErrExpired):
+		c.JSON(http.StatusForbidden, gin.H{"error": "link has expired"})
+	case errors.Is(err, urlsign.ErrUnsigned), errors.Is(err, urlsign.ErrBadSignature), errors.Is(err, urlsign.ErrUnknownKey):
+		c.JSON(http.StatusForbidden, gin.H{"error": "invalid link signature"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot verify link"})
+	}
+	return uuid.Nil, false
+}
+
+func canSeeAttachment(c *gin.Context, svc *service.EventService, a *models.Attachment) (bool, error) {
+	v, err := viewer(c, svc)
+	if err != nil {
+		return false, err
+	}
+	if a.EventID == nil {
+		return v.SeeAll || a.UploadedBy == v.UserID, nil
+	}
+
+	evt, err := svc.GetEvent(*a.EventID)
+	if err != nil {
+		return false, nil
+	}
+	return service.CanView(evt, v), nil
+}
+
+// withAttachment appends id to ids unless it is already there.
+func withAttachment(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	for _, x := range ids {
+		if x == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+// writeAttachmentError answers a rejected upload or attachment list with
+// {error, code, details}, returning false when err is not such a rejection.
+func writeAttachmentError(c *gin.Context, err error) bool {
+	if errors.Is(err, service.ErrAttachmentUnavailable) {
+		err = &service.AttachmentError{Code: service.CodeAttachmentUnavailable, Message: err.Error()}
+	}
+
+	var ae *service.AttachmentError
+	if !errors.As(err, &ae) {
+		return false
+	}
+
+	status := http.StatusBadRequest
+	switch ae.Code {
+	case service.CodeFileTooLarge, service.CodeEventAttachmentsLimit:
+		status = http.StatusRequestEntityTooLarge
+	case service.CodeTypeNotAllowed:
+		status = http.StatusUnsupportedMediaType
+	case service.CodeQuotaExceeded:
+		status = http.StatusForbidden
+	case service.CodeAttachmentQuarantined, service.CodeAttachmentsNotClean, service.CodeCoverNotReady:
+		status = http.StatusConflict
+	}
+
+	details := ae.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+	c.JSON(status, gin.H{"error": ae.Message, "code": ae.Code, "details": details})
+	return true
+}
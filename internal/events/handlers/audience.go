@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"events-service/internal/auth"
+	"events-service/internal/events/models"
+	"events-service/internal/events/repository"
+	"events-service/internal/events/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PreviewAudience reports how many staff an event would reach, so
+// moderators can check the audience before approving.
+func (h *EventHandler) PreviewAudience(c *gin.Context) {
+	svc := h.svc(c)
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	evt, err := svc.GetEvent(eventID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		return
+	}
+
+	audience := evt.Audience.Data()
+	n, err := svc.CountAudience(audience)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot count recipients"})
+		return
+	}
+
+	c.JSON(http.StatusOK, AudiencePreviewResponse{
+		EventID:    eventID.String(),
+		Audience:   audience,
+		Everyone:   audience.Everyone(),
+		Recipients: n,
+	})
+}
+
+// bindAudience validates an audience from a request body, writing a 400 and
+// returning false when it is unusable.
+func bindAudience(c *gin.Context, dto *AudienceDTO) (models.Audience, bool) {
+	if dto == nil {
+		return models.Audience{}, true
+	}
+	a, err := dto.toModel()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "audience.user_ids must be user ids"})
+		return a, false
+	}
+	a, err = service.NormalizeAudience(a)
+	if errors.Is(err, service.ErrInvalidAudience) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return a, false
+	}
+	return a, true
+}
+
+// viewer builds the feed filter for the caller: moderators and admins see
+// every event, everyone else only those addressed to them.
+func viewer(c *gin.Context, svc *service.EventService) (*repository.Viewer, error) {
+	p, ok := auth.FromContext(c)
+	if !ok {
+		return nil, errors.New("no principal")
+	}
+	seeAll := p.Kind == auth.PrincipalAPIKey || p.HasRole(auth.RoleModerator) || p.HasRole(auth.RoleAdmin)
+	return svc.Viewer(p.Subject, seeAll)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+)
+
+// AudienceDTO narrows an event to departments, office locations, job roles
+// and named staff. Omitted or empty means everyone.
+type AudienceDTO struct {
+	Departments []string `json:"departments"`
+	Locations   []string `json:"locations"`
+	Roles       []string `json:"roles"`
+	UserIDs     []string `json:"user_ids"`
+}
+
+type AudiencePreviewResponse struct {
+	EventID    string          `json:"event_id"`
+	Audience   models.Audience `json:"audience"`
+	Everyone   bool            `json:"everyone"`
+	Recipients int64           `json:"recipients"`
+}
+
+func (d *AudienceDTO) toModel() (models.Audience, error) {
+	a := models.Audience{
+		Departments: d.Departments,
+		Locations:   d.Locations,
+		Roles:       d.Roles,
+	}
+	for _, raw := range d.UserIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return a, err
+		}
+		a.UserIDs = append(a.UserIDs, id)
+	}
+	return a, nil
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"events-service/internal/events/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (h *EventHandler) ListAuditLog(c *gin.Context) {
+	svc := h.svc(c)
+	var query AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query params"})
+		return
+	}
+
+	filter := repository.APIAuditFilter{
+		Method:     strings.ToUpper(query.Method),
+		Route:      query.Route,
+		TargetType: query.TargetType,
+		TargetID:   query.TargetID,
+		RequestID:  query.RequestID,
+		StatusCode: query.Status,
+	}
+
+	if query.ActorID != "" {
+		actor, err := uuid.Parse(query.ActorID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid actor_id"})
+			return
+		}
+		filter.ActorID = &actor
+	}
+
+	for _, bound := range []struct {
+		raw  string
+		dest **time.Time
+		name string
+	}{{query.From, &filter.From, "from"}, {query.To, &filter.To, "to"}} {
+		if bound.raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + bound.name})
+			return
+		}
+		*bound.dest = &t
+	}
+
+	if query.Format == "csv" {
+		h.exportAuditLog(c, filter)
+		return
+	}
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.Size <= 0 || query.Size > 200 {
+		query.Size = 50
+	}
+
+	entries, total, err := svc.ListAPIAudit(filter, query.Page, query.Size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot load audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, AuditLogResponse{
+		Page:    query.Page,
+		Size:    query.Size,
+		Total:   total,
+		Entries: entries,
+	})
+}
+
+func (h *EventHandler) exportAuditLog(c *gin.Context, filter repository.APIAuditFilter) {
+	svc := h.svc(c)
+	entries, _, err := svc.ListAPIAudit(filter, 1, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot load audit log"})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="api-audit-`+time.Now().UTC().Format("20060102T150405Z")+`.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{
+		"id", "created_at", "request_id", "actor_id", "actor_kind", "method", "route", "path",
+		"target_type", "target_id", "status_code", "client_ip", "request_digest", "before_digest", "after_digest",
+	})
+
+	for _, e := range entries {
+		actor := ""
+		if e.ActorID != nil {
+			actor = e.ActorID.String()
+		}
+		_ = w.Write([]string{
+			strconv.FormatUint(e.ID, 10),
+			e.CreatedAt.UTC().Format(time.RFC3339Nano),
+			e.RequestID,
+			actor,
+			e.ActorKind,
+			e.Method,
+			e.Route,
+			e.Path,
+			e.TargetType,
+			e.TargetID,
+			strconv.Itoa(e.StatusCode),
+			e.ClientIP,
+			e.RequestDigest,
+			e.BeforeDigest,
+			e.AfterDigest,
+		})
+	}
+
+	w.Flush()
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import "events-service/internal/events/models"
+
+type AuditLogQuery struct {
+	ActorID    string `form:"actor_id"`
+	Method     string `form:"method"`
+	Route      string `form:"route"`
+	TargetType string `form:"target_type"`
+	TargetID   string `form:"target_id"`
+	RequestID  string `form:"request_id"`
+	Status     int    `form:"status"`
+	From       string `form:"from"` // RFC3339, inclusive
+	To         string `form:"to"`   // RFC3339, exclusive
+	Page       int    `form:"page"`
+	Size       int    `form:"size"`
+	Format     string `form:"format"` // json (default) | csv, csv exports every match
+}
+
+type AuditLogResponse struct {
+	Page    int               `json:"page"`
+	Size    int               `json:"size"`
+	Total   int64             `json:"total"`
+	Entries []models.APIAudit `json:"entries"`
+}
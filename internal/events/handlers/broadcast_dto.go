@@ -1,5 +1,5 @@
 package handlers
 
 type ManualBroadcastRequest struct {
-	Channels []string `json:"channels"` // optional, defaults to all
+	Channels []string `json:"channels"` // optional, defaults to every registered channel
 }
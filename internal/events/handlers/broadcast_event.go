@@ -8,6 +8,7 @@ import (
 )
 
 func (h *EventHandler) ManualBroadcast(c *gin.Context) {
+    svc := h.svc(c)
     idStr := c.Param("id")
     eventID, err := uuid.Parse(idStr)
     if err != nil {
@@ -24,32 +25,46 @@ func (h *EventHandler) ManualBroadcast(c *gin.Context) {
 
     // If no channels -> send to all
     if len(req.Channels) == 0 {
-        req.Channels = []string{"fcm", "email", "teams"}
+        req.Channels = svc.Channels.Names()
     }
 
     // fetch event details for payload
-    evt, err := h.Service.GetEvent(eventID)
+    evt, err := svc.GetEvent(eventID)
     if err != nil {
         c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
         return
     }
 
+    // nothing goes out while an attachment is unscanned or infected
+    if writeAttachmentError(c, svc.CheckPublishable(evt)) {
+        return
+    }
+
     // Prepare basic payload
     payload := map[string]any{
         "title":   evt.Title,
         "summary": evt.Summary,
     }
 
+    // only channels this deployment can deliver on, with a payload they accept
+    if err := svc.Channels.Validate(req.Channels, payload); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "available_channels": svc.Channels.Names()})
+        return
+    }
+
     // Enqueue jobs
     for _, ch := range req.Channels {
-        _ = h.Service.EnqueueBroadcast(eventID, ch, payload)
+        if err := svc.EnqueueBroadcast(eventID, ch, payload); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to queue broadcast"})
+            return
+        }
     }
 
     // Increment feed ETag
-    _ = h.Service.IncrementFeedVersion()
+    _ = svc.IncrementFeedVersion()
 
     // Write audit entry for manual broadcast trigger
-    _ = h.Service.CreatePublishAudit(eventID, "manual", "triggered", map[string]any{
+    _ = svc.CreatePublishAudit(eventID, "manual", "triggered", map[string]any{
         "channels": req.Channels,
     })
 
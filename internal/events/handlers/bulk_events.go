@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"events-service/internal/auth"
+	"events-service/internal/events/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var bulkActions = map[string]bool{
+	"moderate":    true,
+	"archive":     true,
+	"add_tags":    true,
+	"remove_tags": true,
+	"delete":      true,
+}
+
+// BulkEvents applies one action to many events. Each event goes through the
+// same service calls as its single-event endpoint; failures are reported per
+// event and the feed version is bumped once if anything changed.
+func (h *EventHandler) BulkEvents(c *gin.Context) {
+	svc := h.svc(c)
+	var dto BulkEventsDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !bulkActions[dto.Action] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action must be one of moderate, archive, add_tags, remove_tags, delete"})
+		return
+	}
+
+	moderator, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var status string
+	switch dto.Action {
+	case "moderate":
+		// the route only needs lifecycle rights; moderating needs its own
+		if p, ok := auth.FromContext(c); !ok || !p.Can(auth.PermEventsModerate) {
+			h.Guard.Deny(c, auth.PermEventsModerate, "missing permission")
+			return
+		}
+		switch dto.Decision {
+		case "approve":
+			status = "approved"
+		case "reject":
+			status = "rejected"
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "decision must be approve or reject"})
+			return
+		}
+
+	case "add_tags", "remove_tags":
+		if len(dto.Tags) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tags are required for " + dto.Action})
+			return
+		}
+	}
+
+	resp := BulkEventsResponse{
+		Action:  dto.Action,
+		Results: make([]BulkEventResult, 0, len(dto.IDs)),
+	}
+
+	for _, idStr := range dto.IDs {
+		result := BulkEventResult{ID: idStr}
+
+		if err := applyBulkAction(svc, idStr, dto, status, moderator); err != nil {
+			result.Error = err.Error()
+			resp.Failed++
+		} else {
+			result.Success = true
+			resp.Succeeded++
+		}
+
+		resp.Results = append(resp.Results, result)
+	}
+
+	// ETag bump, once for the whole batch
+	if resp.Succeeded > 0 {
+		_ = svc.IncrementFeedVersion()
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func applyBulkAction(svc *service.EventService, idStr string, dto BulkEventsDTO, status string, moderator uuid.UUID) error {
+	eventID, err := uuid.Parse(idStr)
+	if err != nil {
+		return errors.New("invalid event id")
+	}
+
+	if _, err := svc.GetEvent(eventID); err != nil {
+		return errors.New("event not found")
+	}
+
+	switch dto.Action {
+	case "moderate":
+		if err := svc.ModerateEvent(eventID, status, moderator, dto.Notes); err != nil {
+			var ae *service.AttachmentError
+			if errors.As(err, &ae) {
+				return ae
+			}
+			if errors.Is(err, service.ErrNotModeratable) {
+				return err
+			}
+			return errors.New("moderation failed")
+		}
+		if status == "approved" {
+			if err := svc.QueueApprovalBroadcasts(eventID); err != nil {
+				return errors.New("moderated but broadcast could not be queued")
+			}
+		}
+
+	case "archive":
+		if err := svc.ArchiveEvent(eventID); err != nil {
+			return errors.New("unable to archive event")
+		}
+
+	case "add_tags":
+		if err := svc.AddEventTags(eventID, dto.Tags); err != nil {
+			return errors.New("unable to add tags")
+		}
+
+	case "remove_tags":
+		if err := svc.RemoveEventTags(eventID, dto.Tags); err != nil {
+			return errors.New("unable to remove tags")
+		}
+
+	case "delete":
+		if err := svc.DeleteEvent(eventID); err != nil {
+			return errors.New("unable to delete event")
+		}
+	}
+
+	return nil
+}
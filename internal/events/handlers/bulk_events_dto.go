@@ -0,0 +1,26 @@
+package handlers
+
+type BulkEventsDTO struct {
+	IDs    []string `json:"ids" binding:"required,min=1,max=200"`
+	Action string   `json:"action" binding:"required"` // moderate | archive | add_tags | remove_tags | delete
+
+	// moderate
+	Decision string `json:"decision"` // approve | reject
+	Notes    string `json:"notes"`
+
+	// add_tags | remove_tags
+	Tags []string `json:"tags"`
+}
+
+type BulkEventResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+type BulkEventsResponse struct {
+	Action    string            `json:"action"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BulkEventResult `json:"results"`
+}
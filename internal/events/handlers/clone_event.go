@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"events-service/internal/events/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (h *EventHandler) CloneEvent(c *gin.Context) {
+	svc := h.svc(c)
+	sourceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	// all fields are optional overrides, so an empty body is fine
+	var dto CloneEventDTO
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&dto); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	createdBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	overrides := service.CloneOverrides{Title: dto.Title}
+	if dto.ScheduledAt != nil && *dto.ScheduledAt != "" {
+		t, err := time.Parse(time.RFC3339, *dto.ScheduledAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scheduled_at"})
+			return
+		}
+		overrides.ScheduledAt = &t
+	}
+
+	eventID, err := svc.CloneEvent(sourceID, createdBy, overrides)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to clone event"})
+		return
+	}
+
+	// ETag bump
+	_ = svc.IncrementFeedVersion()
+
+	c.JSON(http.StatusCreated, gin.H{"id": eventID, "cloned_from": sourceID})
+}
@@ -0,0 +1,6 @@
+package handlers
+
+type CloneEventDTO struct {
+	Title       *string `json:"title"`        // optional override
+	ScheduledAt *string `json:"scheduled_at"` // optional override, RFC3339
+}
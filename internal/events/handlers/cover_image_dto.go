@@ -0,0 +1,14 @@
+package handlers
+
+// CoverImageDTO is an event's cover picture, one URL per rendition.
+type CoverImageDTO struct {
+	ID       string            `json:"id"`
+	Variants []CoverVariantDTO `json:"variants"`
+}
+
+type CoverVariantDTO struct {
+	Name   string `json:"name"` // thumb | medium | large
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/service"
+	"events-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// coverImage lists the renditions of an event's cover with signed links, or
+// nil while there is none or it is still quarantined.
+func coverImage(links *service.LinkSigner, a *models.Attachment) *CoverImageDTO {
+	if a == nil || a.ScanStatus != models.ScanClean || len(a.Variants) == 0 {
+		return nil
+	}
+
+	out := &CoverImageDTO{ID: a.ID.String(), Variants: make([]CoverVariantDTO, 0, len(a.Variants))}
+	for _, v := range a.Variants {
+		out.Variants = append(out.Variants, CoverVariantDTO{
+			Name:   v.Name,
+			URL:    links.Attachment(a, v.Name),
+			Width:  v.Width,
+			Height: v.Height,
+		})
+	}
+	return out
+}
+
+// CoverImage serves a rendition of a published event's cover without a
+// login, for push services and mail clients, through a signed link. The
+// route has no tenant of its own, so the cover is looked up in the tenant
+// that signed the link. Anything else is not found.
+func (h *EventHandler) CoverImage(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "image not found"})
+		return
+	}
+	tenantID, ok := verifyLink(c, h.Service)
+	if !ok {
+		return
+	}
+
+	svc := h.Service.ForTenant(tenantID)
+	a, err := svc.GetPublishedCover(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "image not found"})
+		return
+	}
+
+	writeVariant(c, svc, a, c.Param("variant"), "public, max-age=86400")
+}
+
+// writeVariant streams one rendition of an image attachment.
+func writeVariant(c *gin.Context, svc *service.EventService, a *models.Attachment, name, cacheControl string) {
+	rc, v, err := svc.OpenVariant(c.Request.Context(), a, name)
+	if errors.Is(err, service.ErrNoVariant) || errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "image not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot read image"})
+		return
+	}
+	defer rc.Close()
+
+	c.DataFromReader(http.StatusOK, v.Size, "image/jpeg", rc, map[string]string{
+		"Cache-Control": cacheControl,
+		"ETag":          strconv.Quote(a.Checksum + "-" + name),
+	})
+}
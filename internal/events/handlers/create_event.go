@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"events-service/internal/auth"
 	"events-service/internal/events/models"
 	"events-service/internal/events/repository"
 	"events-service/internal/events/service"
@@ -10,20 +11,23 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
 type EventHandler struct {
     Service *service.EventService
+    Guard   *auth.Guard
 }
 
 func NewEventHandler(db *gorm.DB) *EventHandler {
     repo := repository.NewEventRepository(db)
     svc := service.NewEventService(repo)
-    return &EventHandler{Service: svc}
+    return &EventHandler{Service: svc, Guard: auth.NewGuard(svc)}
 }
 
 func (h *EventHandler) CreateEvent(c *gin.Context) {
+    svc := h.svc(c)
     var dto CreateEventDTO
     if err := c.ShouldBindJSON(&dto); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -39,9 +43,13 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
         scheduledAt = &t
     }
 
-    createdBy, err := uuid.Parse(dto.CreatedBy)
-    if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid created_by"})
+    createdBy, ok := currentUser(c)
+    if !ok {
+        return
+    }
+
+    audience, ok := bindAudience(c, dto.Audience)
+    if !ok {
         return
     }
 
@@ -52,6 +60,13 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
         CreatedBy:   createdBy,
         Status:      "draft",
         ScheduledAt: scheduledAt,
+        Audience:    datatypes.NewJSONType(audience),
+    }
+
+    attachments := dto.Attachments
+    if dto.CoverImage != nil {
+        event.CoverAttachmentID = dto.CoverImage
+        attachments = withAttachment(attachments, *dto.CoverImage)
     }
 
     body := models.AnnouncementBody{
@@ -69,13 +84,17 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
         })
     }
 
-    if err := h.Service.CreateEvent(event, body, tags); err != nil {
+    err := svc.CreateEventWithAttachments(event, body, tags, attachments, createdBy)
+    if writeAttachmentError(c, err) {
+        return
+    }
+    if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
         return
     }
 
     // ETag bump
-    _ = h.Service.IncrementFeedVersion()
+    _ = svc.IncrementFeedVersion()
 
     c.JSON(http.StatusCreated, gin.H{"id": eventID})
 }
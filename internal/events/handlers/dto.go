@@ -1,12 +1,15 @@
 package handlers
 
+import "github.com/google/uuid"
+
 type CreateEventDTO struct {
-    Title       string   `json:"title" binding:"required"`
-    Summary     string   `json:"summary"`
-    Body        string   `json:"body" binding:"required"`
-    Attachments []any    `json:"attachments"`
-    Tags        []string `json:"tags"`
+    Title       string      `json:"title" binding:"required"`
+    Summary     string      `json:"summary"`
+    Body        string      `json:"body" binding:"required"`
+    Attachments []uuid.UUID `json:"attachments"` // ids of the caller's uploads
+    CoverImage  *uuid.UUID  `json:"cover_image"` // optional, an image upload; added to attachments if missing
+    Tags        []string    `json:"tags"`
 
-    CreatedBy   string `json:"created_by" binding:"required"`
-    ScheduledAt string `json:"scheduled_at"` // optional
+    ScheduledAt string       `json:"scheduled_at"` // optional
+    Audience    *AudienceDTO `json:"audience"`     // optional, everyone when omitted
 }
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"events-service/internal/events/service"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,20 +26,22 @@ import (
 // @Failure 500 {object} map[string]string
 // @Router /events [get]
 func (h *EventHandler) ListEvents(c *gin.Context) {
+	svc := h.svc(c)
 
 	// ---- ETag Check (Client Cache Validation) ----
 	clientEtag := c.GetHeader("If-None-Match")
 	// Trim any surrounding quotes or whitespace the client may send
 	clientEtag = strings.TrimSpace(strings.Trim(clientEtag, `"`))
 
-	currentVersion, err := h.Service.GetFeedVersion()
+	currentVersion, err := svc.GetFeedVersion()
 	if err != nil {
 		log.Printf("ListEvents: GetFeedVersion error: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot read feed version"})
 		return
 	}
 
-	etag := strconv.Itoa(currentVersion)
+	// cover links are re-signed per window, so the window is part of the version
+	etag := strconv.Itoa(currentVersion) + "-" + strconv.FormatInt(service.Expiry(time.Now(), svc.Links.TTL).Unix(), 10)
 	log.Printf("ListEvents: clientIfNoneMatch=%q currentVersion=%d etag=%q\n", clientEtag, currentVersion, etag)
 
 	// If client ETag matches server version → no need to send data
@@ -70,13 +74,25 @@ func (h *EventHandler) ListEvents(c *gin.Context) {
 		}
 	}
 
-	events, total, err := h.Service.GetEventFeed(query.Page, query.Size, parsedSince, query.Channel)
+	v, err := viewer(c, svc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot resolve viewer"})
+		return
+	}
+
+	events, total, err := svc.GetEventFeed(query.Page, query.Size, parsedSince, query.Channel, v)
 	if err != nil {
 		log.Printf("ListEvents: GetEventFeed error: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot load events"})
 		return
 	}
 
+	links, err := svc.LinkSigner(svc.Links.TTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot sign links"})
+		return
+	}
+
 	// ---- Build Response ----
 	responseItems := make([]EventFeedItem, 0, len(events))
 
@@ -100,6 +116,7 @@ func (h *EventHandler) ListEvents(c *gin.Context) {
 			ScheduledAt: scheduled,
 			CreatedAt:   e.CreatedAt.Format(time.RFC3339),
 			Tags:        tags,
+			CoverImage:  coverImage(links, e.Cover),
 		})
 	}
 
@@ -17,6 +17,8 @@ type EventFeedItem struct {
 	ScheduledAt *string  `json:"scheduled_at"`
 	CreatedAt   string   `json:"created_at"`
 	Tags        []string `json:"tags"`
+
+	CoverImage *CoverImageDTO `json:"cover_image,omitempty"`
 }
 
 // Final paginated response
@@ -3,11 +3,15 @@ package handlers
 import (
 	"net/http"
 
+	"events-service/internal/events/service"
+	"events-service/internal/render"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 func (h *EventHandler) GetEvent(c *gin.Context) {
+    svc := h.svc(c)
     idStr := c.Param("id")
     eventID, err := uuid.Parse(idStr)
     if err != nil {
@@ -15,11 +19,35 @@ func (h *EventHandler) GetEvent(c *gin.Context) {
         return
     }
 
-    event, err := h.Service.GetEvent(eventID)
+    event, err := svc.GetEvent(eventID)
     if err != nil {
         c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
         return
     }
 
-    c.JSON(http.StatusOK, event)
+    // events outside the caller's audience look the same as missing ones
+    v, err := viewer(c, svc)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot resolve viewer"})
+        return
+    }
+    if !service.CanView(event, v) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+        return
+    }
+
+    links, err := svc.LinkSigner(svc.Links.TTL)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot sign links"})
+        return
+    }
+    for i := range event.Attachments {
+        event.Attachments[i].URL = links.Attachment(&event.Attachments[i], "")
+    }
+
+    c.JSON(http.StatusOK, EventDetailResponse{
+        Event:      event,
+        Rendered:   render.Markdown(event.Body.Body),
+        CoverImage: coverImage(links, event.Cover),
+    })
 }
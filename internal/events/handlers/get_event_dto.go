@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"events-service/internal/events/models"
+	"events-service/internal/render"
+)
+
+// EventDetailResponse is the event as stored plus its body rendered for
+// each channel, so clients do not render Markdown themselves.
+type EventDetailResponse struct {
+	*models.Event
+	Rendered render.Rendered `json:"rendered"`
+
+	CoverImage *CoverImageDTO `json:"cover_image,omitempty"`
+}
@@ -7,9 +7,10 @@ import (
 )
 
 func (h *EventHandler) ListTags(c *gin.Context) {
+    svc := h.svc(c)
     q := c.Query("query")
 
-    tags, err := h.Service.SearchGlobalTags(q)
+    tags, err := svc.SearchGlobalTags(q)
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot load tags"})
         return
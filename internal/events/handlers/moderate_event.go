@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	"events-service/internal/events/service"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 func (h *EventHandler) ModerateEvent(c *gin.Context) {
+    svc := h.svc(c)
     eventIDStr := c.Param("id")
     eventID, err := uuid.Parse(eventIDStr)
     if err != nil {
@@ -26,9 +31,8 @@ func (h *EventHandler) ModerateEvent(c *gin.Context) {
         return
     }
 
-    moderator, err := uuid.Parse(dto.ModeratorID)
-    if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid moderator_id"})
+    moderator, ok := currentUser(c)
+    if !ok {
         return
     }
 
@@ -39,30 +43,32 @@ func (h *EventHandler) ModerateEvent(c *gin.Context) {
         status = "rejected"
     }
 
-    if err := h.Service.ModerateEvent(eventID, status, moderator, dto.Notes); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "moderation failed"})
+    if err := svc.ModerateEvent(eventID, status, moderator, dto.Notes); err != nil {
+        if writeAttachmentError(c, err) {
+            return
+        }
+        switch {
+        case errors.Is(err, gorm.ErrRecordNotFound):
+            c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+        case errors.Is(err, service.ErrNotModeratable):
+            c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+        default:
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "moderation failed"})
+        }
         return
     }
 
     // ETag bump
-    _ = h.Service.IncrementFeedVersion()
+    _ = svc.IncrementFeedVersion()
 
+    // the moderation stands; report that nothing will go out
     if status == "approved" {
-    // fetch event details to include title/summary
-    evt, _ := h.Service.GetEvent(eventID)
-
-    payload := map[string]any{
-        "title":   evt.Title,
-        "summary": evt.Summary,
+        if err := svc.QueueApprovalBroadcasts(eventID); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "moderated but broadcast could not be queued", "status": status})
+            return
+        }
     }
 
-    _ = h.Service.EnqueueBroadcast(eventID, "fcm", payload)
-    
-    _ = h.Service.EnqueueBroadcast(eventID, "email", nil)
-    _ = h.Service.EnqueueBroadcast(eventID, "teams", nil)
-}
-
-
     c.JSON(http.StatusOK, gin.H{
         "message": "event moderated",
         "status":  status,
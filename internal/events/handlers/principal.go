@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"events-service/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// currentUser returns the authenticated caller's id, or writes a 401 and
+// returns false when the request carries no principal.
+func currentUser(c *gin.Context) (uuid.UUID, bool) {
+	p, ok := auth.FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return uuid.Nil, false
+	}
+	return p.Subject, true
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"events-service/internal/events/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (h *EventHandler) RetractEvent(c *gin.Context) {
+	svc := h.svc(c)
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var dto RetractEventDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	retractedBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	result, err := svc.RetractEvent(eventID, dto.Reason, retractedBy, dto.Notify)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		return
+	case errors.Is(err, service.ErrNotRetractable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil && result == nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to retract event"})
+		return
+	case err != nil:
+		// retracted, but the recall notice could not be fully queued
+		_ = svc.IncrementFeedVersion()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "event retracted but recall could not be queued", "result": result})
+		return
+	}
+
+	// ETag bump
+	_ = svc.IncrementFeedVersion()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "event retracted",
+		"cancelled_jobs":  result.CancelledJobs,
+		"recall_channels": result.RecallChannels,
+	})
+}
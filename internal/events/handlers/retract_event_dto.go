@@ -0,0 +1,6 @@
+package handlers
+
+type RetractEventDTO struct {
+	Reason string `json:"reason" binding:"required"`
+	Notify bool   `json:"notify"` // send a withdrawal notice on the original channels
+}
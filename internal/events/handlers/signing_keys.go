@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSigningKeys shows the tenant's link signing keys without their
+// secrets.
+func (h *EventHandler) ListSigningKeys(c *gin.Context) {
+	svc := h.svc(c)
+	keys, err := svc.ListSigningKeys()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot load signing keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"signing_keys": keys})
+}
+
+// RotateSigningKey starts signing the tenant's links with a fresh key.
+// Links signed with earlier keys keep working until they expire.
+func (h *EventHandler) RotateSigningKey(c *gin.Context) {
+	svc := h.svc(c)
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	key, err := svc.RotateSigningKey(admin)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to rotate signing key"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"signing_key": key})
+}
@@ -0,0 +1,31 @@
+package handlers
+
+type TemplateVariableDTO struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type CreateTemplateDTO struct {
+	Name        string                `json:"name" binding:"required"`
+	Title       string                `json:"title" binding:"required"`
+	Summary     string                `json:"summary"`
+	Body        string                `json:"body" binding:"required"`
+	DefaultTags []string              `json:"default_tags"`
+	Variables   []TemplateVariableDTO `json:"variables"`
+}
+
+type UpdateTemplateDTO struct {
+	Name        *string               `json:"name"`
+	Title       *string               `json:"title"`
+	Summary     *string               `json:"summary"`
+	Body        *string               `json:"body"`
+	DefaultTags []string              `json:"default_tags"`
+	Variables   []TemplateVariableDTO `json:"variables"`
+}
+
+type EventFromTemplateDTO struct {
+	Variables map[string]string `json:"variables"`
+	Tags      []string          `json:"tags"` // merged with the template's default tags
+
+	ScheduledAt string `json:"scheduled_at"` // optional
+}
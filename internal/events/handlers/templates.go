@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func toTemplateVariables(in []TemplateVariableDTO) []models.TemplateVariable {
+	vars := make([]models.TemplateVariable, 0, len(in))
+	for _, v := range in {
+		vars = append(vars, models.TemplateVariable{Name: v.Name, Description: v.Description})
+	}
+	return vars
+}
+
+func (h *EventHandler) CreateTemplate(c *gin.Context) {
+	svc := h.svc(c)
+	var dto CreateTemplateDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	createdBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if dto.DefaultTags == nil {
+		dto.DefaultTags = []string{}
+	}
+
+	tpl, err := svc.CreateTemplate(models.EventTemplate{
+		Name:        dto.Name,
+		Title:       dto.Title,
+		Summary:     dto.Summary,
+		Body:        dto.Body,
+		DefaultTags: dto.DefaultTags,
+		Variables:   toTemplateVariables(dto.Variables),
+		CreatedBy:   createdBy,
+	})
+	if errors.Is(err, service.ErrInvalidTemplate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to create template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tpl)
+}
+
+func (h *EventHandler) ListTemplates(c *gin.Context) {
+	svc := h.svc(c)
+	templates, err := svc.ListTemplates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot load templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+func (h *EventHandler) GetTemplate(c *gin.Context) {
+	svc := h.svc(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	tpl, err := svc.GetTemplate(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tpl)
+}
+
+func (h *EventHandler) UpdateTemplate(c *gin.Context) {
+	svc := h.svc(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var dto UpdateTemplateDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tpl, err := svc.GetTemplate(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	}
+
+	if dto.Name != nil {
+		tpl.Name = *dto.Name
+	}
+	if dto.Title != nil {
+		tpl.Title = *dto.Title
+	}
+	if dto.Summary != nil {
+		tpl.Summary = *dto.Summary
+	}
+	if dto.Body != nil {
+		tpl.Body = *dto.Body
+	}
+	if dto.DefaultTags != nil {
+		tpl.DefaultTags = dto.DefaultTags
+	}
+	if dto.Variables != nil {
+		tpl.Variables = toTemplateVariables(dto.Variables)
+	}
+
+	err = svc.UpdateTemplate(*tpl)
+	if errors.Is(err, service.ErrInvalidTemplate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to update template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tpl)
+}
+
+func (h *EventHandler) DeleteTemplate(c *gin.Context) {
+	svc := h.svc(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = svc.DeleteTemplate(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to delete template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "template deleted"})
+}
+
+func (h *EventHandler) CreateEventFromTemplate(c *gin.Context) {
+	svc := h.svc(c)
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+
+	var dto EventFromTemplateDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	createdBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var scheduledAt *time.Time
+	if dto.ScheduledAt != "" {
+		t, err := time.Parse(time.RFC3339, dto.ScheduledAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scheduled_at"})
+			return
+		}
+		scheduledAt = &t
+	}
+
+	eventID, err := svc.CreateEventFromTemplate(templateID, dto.Variables, createdBy, scheduledAt, dto.Tags)
+	var missing *service.MissingVariablesError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	case errors.As(err, &missing):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing template variables", "missing": missing.Missing})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// ETag bump
+	_ = svc.IncrementFeedVersion()
+
+	c.JSON(http.StatusCreated, gin.H{"id": eventID})
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"events-service/internal/events/service"
+	"events-service/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// svc returns the service scoped to the request's tenant. Event, tag, feed,
+// broadcast, template, API key and audit log handlers must go through it
+// rather than h.Service, which sees every tenant. tenant.Middleware guarantees a tenant on /api/v1, so a
+// missing one is a wiring bug.
+func (h *EventHandler) svc(c *gin.Context) *service.EventService {
+	t, ok := tenant.FromContext(c)
+	if !ok {
+		panic("handlers: tenant.Middleware not installed")
+	}
+	return h.Service.ForTenant(t.ID)
+}
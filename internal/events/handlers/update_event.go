@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"events-service/internal/auth"
 	"events-service/internal/events/models"
+	"events-service/internal/events/service"
 	"net/http"
 	"time"
 
@@ -9,7 +11,29 @@ import (
 	"github.com/google/uuid"
 )
 
+// canEditEvent lets moderators and admins edit any event. Authors may edit
+// drafts they created, and correct their own delivered events as long as
+// the edit is flagged as a correction or significant update, which
+// re-notifies. See EventService.AuthorMayEdit.
+func canEditEvent(svc *service.EventService, p *auth.Principal, evt *models.Event, changeType string) (bool, error) {
+    if p == nil {
+        return false, nil
+    }
+    if isModerator(p) {
+        return true, nil
+    }
+    if evt.CreatedBy != p.Subject {
+        return false, nil
+    }
+    return svc.AuthorMayEdit(evt, changeType)
+}
+
+func isModerator(p *auth.Principal) bool {
+    return p.HasRole(auth.RoleAdmin) || p.HasRole(auth.RoleModerator)
+}
+
 func (h *EventHandler) UpdateEvent(c *gin.Context) {
+    svc := h.svc(c)
     idStr := c.Param("id")
     eventID, err := uuid.Parse(idStr)
     if err != nil {
@@ -23,6 +47,44 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
         return
     }
 
+    if dto.ChangeType == "" {
+        dto.ChangeType = service.ChangeMinor
+    }
+    if !service.ValidChangeType(dto.ChangeType) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "change_type must be minor, correction or significant_update"})
+        return
+    }
+
+    audience, ok := bindAudience(c, dto.Audience)
+    if !ok {
+        return
+    }
+
+    before, err := svc.GetEvent(eventID)
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+        return
+    }
+
+    p, _ := auth.FromContext(c)
+    allowed, err := canEditEvent(svc, p, before, dto.ChangeType)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot check delivery"})
+        return
+    }
+    if !allowed {
+        h.Guard.Deny(c, auth.PermEventsWrite, "authors may only edit their own drafts or flag corrections to their own delivered events")
+        return
+    }
+    // a correction re-announces the text; who gets it and what is attached
+    // stay as moderated
+    if !isModerator(p) && before.Status != "draft" &&
+        (dto.Audience != nil || dto.Attachments != nil || dto.CoverImage != nil) {
+        h.Guard.Deny(c, auth.PermEventsWrite, "authors cannot change the audience, attachments or cover of a delivered event")
+        return
+    }
+    editor := p.Subject
+
     eventUpdates := models.Event{
         ID: eventID,
     }
@@ -44,9 +106,6 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
     if dto.Body != nil {
         bodyUpdates.Body = *dto.Body
     }
-    if dto.Attachments != nil {
-        bodyUpdates.Attachments = []byte("[]")
-    }
 
     var tags []models.EventTag
     for _, t := range dto.Tags {
@@ -56,13 +115,78 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
         })
     }
 
-    if err := h.Service.UpdateEvent(eventUpdates, bodyUpdates, tags); err != nil {
+    // the cover must be among the attachments the event ends up with
+    var cover *uuid.UUID
+    if dto.CoverImage != nil && *dto.CoverImage != "" {
+        id, err := uuid.Parse(*dto.CoverImage)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cover_image"})
+            return
+        }
+        cover = &id
+
+        attachments := dto.Attachments
+        if attachments != nil {
+            dto.Attachments = withAttachment(dto.Attachments, id)
+            attachments = dto.Attachments
+        } else {
+            for _, a := range before.Attachments {
+                attachments = append(attachments, a.ID)
+            }
+        }
+        if writeAttachmentError(c, svc.CheckCover(id, attachments)) {
+            return
+        }
+    }
+
+    // attachments first: an unusable id rejects the edit before anything changes
+    if dto.Attachments != nil {
+        err := svc.SetEventAttachments(eventID, dto.Attachments, editor)
+        if writeAttachmentError(c, err) {
+            return
+        }
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to update attachments"})
+            return
+        }
+    }
+
+    if err := svc.UpdateEvent(eventUpdates, bodyUpdates, tags); err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to update event"})
         return
     }
+    if dto.CoverImage != nil {
+        err := svc.SetEventCover(eventID, cover)
+        if writeAttachmentError(c, err) {
+            return
+        }
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to update cover image"})
+            return
+        }
+    }
+    if dto.Audience != nil {
+        if err := svc.SetEventAudience(eventID, audience); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to update audience"})
+            return
+        }
+    }
 
     // ETag bump
-    _ = h.Service.IncrementFeedVersion()
+    _ = svc.IncrementFeedVersion()
+
+    // Corrections and significant updates to an already-sent event are re-announced
+    notified := []string{}
+    if dto.ChangeType != service.ChangeMinor {
+        after, err := svc.GetEvent(eventID)
+        if err == nil {
+            notified, err = svc.NotifyMaterialUpdate(before, after, dto.ChangeType, dto.ChangeNote)
+        }
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "updated but the update notice could not be queued", "notified_channels": notified})
+            return
+        }
+    }
 
-    c.JSON(http.StatusOK, gin.H{"message": "event updated"})
+    c.JSON(http.StatusOK, gin.H{"message": "event updated", "notified_channels": notified})
 }
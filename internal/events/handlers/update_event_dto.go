@@ -1,10 +1,18 @@
 package handlers
 
+import "github.com/google/uuid"
+
 type UpdateEventDTO struct {
-	Title       *string  `json:"title"`
-	Summary     *string  `json:"summary"`
-	Body        *string  `json:"body"`
-	Attachments []any    `json:"attachments"`
-	Tags        []string `json:"tags"`
-	ScheduledAt *string  `json:"scheduled_at"`
+	Title       *string     `json:"title"`
+	Summary     *string     `json:"summary"`
+	Body        *string     `json:"body"`
+	Attachments []uuid.UUID `json:"attachments"` // replaces the attachments; [] removes them all
+	CoverImage  *string     `json:"cover_image"` // an image attachment's id; "" removes the cover
+	Tags        []string    `json:"tags"`
+	ScheduledAt *string     `json:"scheduled_at"`
+
+	Audience *AudienceDTO `json:"audience"` // replaces the audience; {} means everyone
+
+	ChangeType string `json:"change_type"` // minor (default) | correction | significant_update
+	ChangeNote string `json:"change_note"` // optional, shown to recipients instead of the generated summary
 }
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserRole grants a role to a user within one tenant.
+type UserRole struct {
+	TenantID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Role      string    `gorm:"primaryKey" json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (UserRole) TableName() string {
+	return "user_roles"
+}
+
+// AccessAudit records a request refused by role-based access control.
+type AccessAudit struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	ActorID    *uuid.UUID `gorm:"type:uuid" json:"actor_id"`
+	Method     string     `json:"method"`
+	Route      string     `json:"route"`
+	Permission string     `json:"permission"`
+	Reason     string     `json:"reason"`
+	ClientIP   string     `json:"client_ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (AccessAudit) TableName() string {
+	return "access_audit"
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIAudit is an append-only record of a mutating API call.
+type APIAudit struct {
+	ID            uint64     `gorm:"primaryKey" json:"id"`
+	TenantID      uuid.UUID  `gorm:"type:uuid" json:"-"`
+	RequestID     string     `json:"request_id"`
+	ActorID       *uuid.UUID `gorm:"type:uuid" json:"actor_id"`
+	ActorKind     string     `json:"actor_kind"`
+	Method        string     `json:"method"`
+	Route         string     `json:"route"`
+	Path          string     `json:"path"`
+	TargetType    string     `json:"target_type"`
+	TargetID      string     `json:"target_id"`
+	StatusCode    int        `json:"status_code"`
+	ClientIP      string     `json:"client_ip"`
+	RequestDigest string     `json:"request_digest"`
+	BeforeDigest  string     `json:"before_digest"`
+	AfterDigest   string     `json:"after_digest"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (APIAudit) TableName() string {
+	return "api_audit"
+}
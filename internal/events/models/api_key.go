@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// APIKey is an admin-issued credential for machine clients. Only the SHA-256
+// hash of the key is stored.
+type APIKey struct {
+	ID         uuid.UUID                   `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID   uuid.UUID                   `gorm:"type:uuid" json:"-"`
+	Name       string                      `json:"name"`
+	Prefix     string                      `json:"prefix"`
+	KeyHash    string                      `json:"-"`
+	Scopes     datatypes.JSONSlice[string] `gorm:"type:jsonb" json:"scopes"`
+	ExpiresAt  time.Time                   `json:"expires_at"`
+	LastUsedAt *time.Time                  `json:"last_used_at"`
+	RevokedAt  *time.Time                  `json:"revoked_at"`
+	CreatedBy  uuid.UUID                   `gorm:"type:uuid" json:"created_by"`
+	CreatedAt  time.Time                   `json:"created_at"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
@@ -0,0 +1,18 @@
+package models
+
+import "github.com/google/uuid"
+
+// AppUser is a staff directory entry; the directory itself is maintained
+// outside this service.
+type AppUser struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID   uuid.UUID `gorm:"type:uuid" json:"-"`
+	Email      string    `json:"email"`
+	Department string    `json:"department"`
+	Location   string    `json:"location"`
+	JobRole    string    `json:"job_role"`
+}
+
+func (AppUser) TableName() string {
+	return "app_users"
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Malware scan states. Anything but ScanClean is quarantined: it cannot be
+// downloaded or published.
+const (
+	ScanPending  = "pending"
+	ScanClean    = "clean"
+	ScanInfected = "infected"
+	ScanError    = "scan_error" // the scanner kept failing; no more retries
+)
+
+// Attachment is an uploaded file. The bytes live in storage under
+// StorageKey; EventID stays nil until the upload is attached to an event.
+type Attachment struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID   uuid.UUID  `gorm:"type:uuid" json:"tenant_id"`
+	EventID    *uuid.UUID `gorm:"type:uuid" json:"event_id"`
+	Name       string     `json:"name"`
+	Size       int64      `json:"size"`
+	MimeType   string     `json:"mime_type"`
+	Checksum   string     `json:"checksum"` // hex SHA-256 of the content
+	StorageKey string     `json:"-"`
+	UploadedBy uuid.UUID  `gorm:"type:uuid" json:"uploaded_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	ScanStatus    string     `gorm:"default:pending" json:"scan_status"`
+	ScanSignature string     `json:"scan_signature,omitempty"`
+	ScannedAt     *time.Time `json:"scanned_at"`
+	ScanAttempts  int        `gorm:"default:0" json:"-"` // failed attempts so far
+	NextScanAt    *time.Time `json:"-"`                  // when a pending scan is retried
+
+	// resized JPEG renditions, made by the scan worker once an image scans
+	// clean; VariantBytes is their total size
+	Variants      datatypes.JSONSlice[ImageVariant] `gorm:"type:jsonb" json:"variants,omitempty"`
+	NeedsVariants bool                              `json:"-"`
+	VariantBytes  int64                             `json:"-"`
+
+	// signed, expiring download link, filled in for responses
+	URL string `gorm:"-" json:"url,omitempty"`
+}
+
+// ImageVariant is one stored rendition of an image attachment.
+type ImageVariant struct {
+	Name   string `json:"name"` // thumb | medium | large
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int64  `json:"size"`
+}
+
+// VariantKey is where a rendition is stored, next to the original. Clones
+// share the original's key and so its renditions too.
+func (a *Attachment) VariantKey(name string) string {
+	return a.StorageKey + "." + name + ".jpg"
+}
+
+// Variant looks up a rendition by name.
+func (a *Attachment) Variant(name string) (ImageVariant, bool) {
+	for _, v := range a.Variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return ImageVariant{}, false
+}
+
+func (Attachment) TableName() string {
+	return "attachments"
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Audience narrows who an event is for. Staff match when they are listed in
+// UserIDs, or when they fall within every non-empty dimension of
+// Departments, Locations and Roles. An empty audience is everyone in the
+// tenant.
+type Audience struct {
+	Departments []string    `json:"departments,omitempty"`
+	Locations   []string    `json:"locations,omitempty"`
+	Roles       []string    `json:"roles,omitempty"` // job roles from the staff directory
+	UserIDs     []uuid.UUID `json:"user_ids,omitempty"`
+}
+
+// Everyone reports whether the audience places no restriction.
+func (a Audience) Everyone() bool {
+	return !a.HasDimensions() && len(a.UserIDs) == 0
+}
+
+// HasDimensions reports whether any of department, location or role is set.
+func (a Audience) HasDimensions() bool {
+	return len(a.Departments) > 0 || len(a.Locations) > 0 || len(a.Roles) > 0
+}
+
+// Normalize lower-cases, trims, de-duplicates and sorts every list so
+// audiences compare and match case-insensitively.
+func (a Audience) Normalize() Audience {
+	out := Audience{
+		Departments: normalizeValues(a.Departments),
+		Locations:   normalizeValues(a.Locations),
+		Roles:       normalizeValues(a.Roles),
+	}
+
+	seen := map[uuid.UUID]bool{}
+	for _, id := range a.UserIDs {
+		if id != uuid.Nil && !seen[id] {
+			seen[id] = true
+			out.UserIDs = append(out.UserIDs, id)
+		}
+	}
+	sort.Slice(out.UserIDs, func(i, j int) bool { return out.UserIDs[i].String() < out.UserIDs[j].String() })
+
+	return out
+}
+
+// Includes reports whether the staff member is in the audience.
+func (a Audience) Includes(u AppUser) bool {
+	if a.Everyone() {
+		return true
+	}
+	for _, id := range a.UserIDs {
+		if id == u.ID {
+			return true
+		}
+	}
+	if !a.HasDimensions() {
+		return false
+	}
+	return matchesDimension(a.Departments, u.Department) &&
+		matchesDimension(a.Locations, u.Location) &&
+		matchesDimension(a.Roles, u.JobRole)
+}
+
+func matchesDimension(allowed []string, value string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, v := range allowed {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeValues(in []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, v := range in {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v != "" && !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
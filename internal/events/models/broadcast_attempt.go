@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BroadcastAttempt is one failed delivery of a broadcast job, kept so
+// admins can see why a job ended up failed.
+type BroadcastAttempt struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TenantID  uuid.UUID `gorm:"type:uuid" json:"tenant_id"`
+	JobID     int       `json:"job_id"`
+	Attempt   int       `json:"attempt"`
+	Error     string    `json:"error"`
+	Retryable bool      `json:"retryable"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (BroadcastAttempt) TableName() string {
+	return "broadcast_attempts"
+}
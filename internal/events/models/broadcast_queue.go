@@ -10,12 +10,16 @@ import (
 
 type BroadcastQueue struct {
 	ID        int            `gorm:"primaryKey" json:"id"`
+	TenantID  uuid.UUID      `gorm:"type:uuid" json:"tenant_id"`
 	EventID   uuid.UUID      `gorm:"type:uuid" json:"event_id"`
 	Channel   string         `json:"channel"`
 	Payload datatypes.JSONMap `gorm:"type:jsonb" json:"payload"`
 	Status    string         `json:"status"`
 	Attempts  int            `json:"attempts"`
 	LastError *string        `json:"last_error"`
+	NextAttemptAt *time.Time `json:"next_attempt_at"` // set while a failed job waits to be retried
+	LeaseOwner *string `json:"lease_owner"` // worker holding a processing job
+	LeaseExpiresAt *time.Time `json:"lease_expires_at"` // extended while the worker is alive
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 }
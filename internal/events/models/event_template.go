@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// TemplateVariable is a placeholder declared by a template, referenced
+// in its title, summary or body as {{name}}.
+type TemplateVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type EventTemplate struct {
+	ID          uuid.UUID                             `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID    uuid.UUID                             `gorm:"type:uuid" json:"-"`
+	Name        string                                `json:"name"`
+	Title       string                                `json:"title"`
+	Summary     string                                `json:"summary"`
+	Body        string                                `json:"body"`
+	DefaultTags datatypes.JSONSlice[string]           `gorm:"type:jsonb" json:"default_tags"`
+	Variables   datatypes.JSONSlice[TemplateVariable] `gorm:"type:jsonb" json:"variables"`
+	CreatedBy   uuid.UUID                             `gorm:"type:uuid" json:"created_by"`
+	CreatedAt   time.Time                             `json:"created_at"`
+	UpdatedAt   time.Time                             `json:"updated_at"`
+}
+
+func (EventTemplate) TableName() string {
+	return "event_templates"
+}
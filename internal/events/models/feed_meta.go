@@ -1,9 +1,15 @@
 package models
 
-import "time"
+import (
+    "time"
 
+    "github.com/google/uuid"
+)
+
+// FeedMeta holds one feed version per tenant.
 type FeedMeta struct {
     ID        int       `gorm:"primaryKey"`
+    TenantID  uuid.UUID `gorm:"type:uuid" json:"tenant_id"`
     Version   int       `json:"version"`
     UpdatedAt time.Time `json:"updated_at"`
 }
@@ -1,8 +1,11 @@
 package models
 
+import "github.com/google/uuid"
+
 type GlobalTag struct {
-	ID  int    `gorm:"primaryKey"`
-	Tag string `json:"tag"`
+	ID       int       `gorm:"primaryKey"`
+	TenantID uuid.UUID `gorm:"type:uuid" json:"-"`
+	Tag      string    `json:"tag"`
 }
 
 func (GlobalTag) TableName() string {
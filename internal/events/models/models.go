@@ -9,6 +9,7 @@ import (
 
 type Event struct {
     ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+    TenantID    uuid.UUID `gorm:"type:uuid"`
     Title       string
     Summary     string
     CreatedBy   uuid.UUID `gorm:"type:uuid"`
@@ -18,15 +19,22 @@ type Event struct {
     CreatedAt   time.Time
     UpdatedAt   time.Time
 
-    Body AnnouncementBody `gorm:"foreignKey:EventID"`
-    Tags []EventTag       `gorm:"foreignKey:EventID"`
+    Audience datatypes.JSONType[Audience] `gorm:"type:jsonb"`
+
+    // optional cover picture, one of the event's image attachments
+    CoverAttachmentID *uuid.UUID  `gorm:"type:uuid"`
+    Cover             *Attachment `gorm:"foreignKey:CoverAttachmentID"`
+
+    Body        AnnouncementBody `gorm:"foreignKey:EventID"`
+    Tags        []EventTag       `gorm:"foreignKey:EventID"`
+    Attachments []Attachment     `gorm:"foreignKey:EventID"`
 }
 
 type AnnouncementBody struct {
     ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
     EventID     uuid.UUID `gorm:"type:uuid"`
     Body        string
-    Attachments []byte `gorm:"type:jsonb"`
+    Attachments []byte `gorm:"type:jsonb"` // legacy, superseded by Event.Attachments
 }
 
 type EventTag struct {
@@ -37,13 +45,33 @@ type EventTag struct {
 
 type PublishAudit struct {
     ID        uint           `gorm:"primaryKey" json:"id"`
+    TenantID  uuid.UUID      `gorm:"type:uuid" json:"tenant_id"`
     EventID   uuid.UUID      `gorm:"type:uuid" json:"event_id"`
     Channel   string         `json:"channel"`  // "moderation", "fcm", "email", "teams"
     Status    string         `json:"status"`   // approved, rejected, sent, failed
     Details   datatypes.JSON `gorm:"type:jsonb" json:"details,omitempty"` // Changed this line
     CreatedAt time.Time      `json:"created_at"`
+
+    // tamper-evident chain, see repository.PublishAuditHash
+    PrevHash    string `json:"prev_hash"`
+    Hash        string `json:"hash"`
+    HashVersion int    `gorm:"default:1" json:"hash_version"`
 }
 
 func (PublishAudit) TableName() string {
     return "publish_audit"
-}
\ No newline at end of file
+}
+
+// PublishAuditHead is the single row recording the newest hash of the
+// publish_audit chain and how many rows it links, so rows deleted from the
+// end of the chain can be told apart from a chain that ends there.
+type PublishAuditHead struct {
+    ID        int       `gorm:"primaryKey" json:"-"`
+    Hash      string    `json:"hash"`
+    Chained   int64     `json:"chained"`
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (PublishAuditHead) TableName() string {
+    return "publish_audit_head"
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SigningKey is an HMAC secret for a tenant's signed download links. The
+// tenant's newest key without RetiredAt signs; retired keys still verify
+// until ExpiresAt, when every link they signed has expired.
+type SigningKey struct {
+	ID        string     `gorm:"primaryKey" json:"id"`
+	TenantID  uuid.UUID  `gorm:"type:uuid" json:"-"`
+	Secret    []byte     `json:"-"`
+	CreatedBy *uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (SigningKey) TableName() string {
+	return "signing_keys"
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tenant is a client organisation served by this deployment, with the
+// sender identities its broadcasts go out under.
+type Tenant struct {
+	ID                 uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Slug               string    `json:"slug"`
+	Name               string    `json:"name"`
+	Host               *string   `json:"host"`
+	SESSource          string    `gorm:"column:ses_source" json:"ses_source"`
+	FCMCredentialsFile string    `gorm:"column:fcm_credentials_file" json:"-"`
+	FCMTopic           string    `gorm:"column:fcm_topic" json:"fcm_topic"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+func (Tenant) TableName() string {
+	return "tenants"
+}
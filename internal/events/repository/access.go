@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+)
+
+func (r *EventRepository) GetUserRoles(userID uuid.UUID) ([]string, error) {
+	var roles []string
+	err := r.DB.Model(&models.UserRole{}).
+		Where("user_id = ?", userID).
+		Order("role ASC").
+		Pluck("role", &roles).Error
+	return roles, err
+}
+
+func (r *EventRepository) CreateAccessAudit(entry *models.AccessAudit) error {
+	return r.DB.Create(entry).Error
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"events-service/internal/events/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIAuditFilter struct {
+	ActorID    *uuid.UUID
+	Method     string
+	Route      string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	StatusCode int
+	From       *time.Time
+	To         *time.Time
+}
+
+func (r *EventRepository) CreateAPIAudit(entry *models.APIAudit) error {
+	return r.DB.Create(entry).Error
+}
+
+// ListAPIAudit returns matching entries newest first. A size of 0 returns
+// every match, for exports.
+func (r *EventRepository) ListAPIAudit(f APIAuditFilter, page, size int) ([]models.APIAudit, int64, error) {
+	var entries []models.APIAudit
+	var total int64
+
+	q := r.DB.Model(&models.APIAudit{})
+
+	if f.ActorID != nil {
+		q = q.Where("actor_id = ?", *f.ActorID)
+	}
+	if f.Method != "" {
+		q = q.Where("method = ?", f.Method)
+	}
+	if f.Route != "" {
+		q = q.Where("route = ?", f.Route)
+	}
+	if f.TargetType != "" {
+		q = q.Where("target_type = ?", f.TargetType)
+	}
+	if f.TargetID != "" {
+		q = q.Where("target_id = ?", f.TargetID)
+	}
+	if f.RequestID != "" {
+		q = q.Where("request_id = ?", f.RequestID)
+	}
+	if f.StatusCode != 0 {
+		q = q.Where("status_code = ?", f.StatusCode)
+	}
+	if f.From != nil {
+		q = q.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("created_at < ?", *f.To)
+	}
+
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	q = q.Order("created_at DESC, id DESC")
+	if size > 0 {
+		q = q.Offset((page - 1) * size).Limit(size)
+	}
+
+	if err := q.Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
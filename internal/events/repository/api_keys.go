@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"events-service/internal/events/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r *EventRepository) CreateAPIKey(key *models.APIKey) error {
+	return r.DB.Create(key).Error
+}
+
+func (r *EventRepository) ListAPIKeys() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := r.DB.Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *EventRepository) GetAPIKeyByPrefix(prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.DB.First(&key, "prefix = ?", prefix).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *EventRepository) TouchAPIKey(id uuid.UUID, at time.Time) error {
+	return r.DB.Model(&models.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
+
+func (r *EventRepository) RevokeAPIKey(id uuid.UUID) error {
+	res := r.DB.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,394 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"events-service/internal/events/models"
+	"events-service/internal/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ErrAttachmentUnavailable is returned when an event names an attachment
+// that does not exist, belongs to another event or was uploaded by someone
+// else.
+var ErrAttachmentUnavailable = errors.New("attachment not found or not available to this event")
+
+// ErrQuotaExceeded is returned when an upload would take its author over
+// their storage quota.
+var ErrQuotaExceeded = errors.New("attachment storage quota exceeded")
+
+func (r *EventRepository) CreateAttachment(a *models.Attachment) error {
+	return r.DB.Create(a).Error
+}
+
+func (r *EventRepository) GetAttachment(id uuid.UUID) (*models.Attachment, error) {
+	var a models.Attachment
+	if err := r.DB.First(&a, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetAttachments loads the given attachments, skipping unknown ids.
+func (r *EventRepository) GetAttachments(ids []uuid.UUID) ([]models.Attachment, error) {
+	var rows []models.Attachment
+	if err := r.DB.Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// AuthorAttachmentBytes is how much storage an author's live uploads and
+// their renditions take; deleted and swept uploads have no rows left.
+// Clones share their source's objects, so each object is counted once.
+func (r *EventRepository) AuthorAttachmentBytes(uploadedBy uuid.UUID) (int64, error) {
+	tenantID, err := r.tenantID()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	err = r.DB.Raw(`
+		SELECT COALESCE(SUM(size + variant_bytes), 0) FROM (
+			SELECT DISTINCT storage_key, size, variant_bytes FROM attachments
+			WHERE tenant_id = ? AND uploaded_by = ?
+		) objects
+	`, tenantID, uploadedBy).Scan(&total).Error
+	return total, err
+}
+
+// CreateAttachmentWithinQuota inserts a unless its uploader would then use
+// more than quota, and returns what they used before. On Postgres uploads
+// by one author take a lock, so concurrent ones cannot all pass the check.
+func (r *EventRepository) CreateAttachmentWithinQuota(a *models.Attachment, quota int64) (int64, error) {
+	var used int64
+	err := r.Transaction(func(repo *EventRepository) error {
+		if repo.DB.Dialector.Name() == "postgres" {
+			if err := repo.DB.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))",
+				"attachment-quota:"+a.UploadedBy.String()).Error; err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if used, err = repo.AuthorAttachmentBytes(a.UploadedBy); err != nil {
+			return err
+		}
+		if used+a.Size > quota {
+			return ErrQuotaExceeded
+		}
+		return repo.CreateAttachment(a)
+	})
+	return used, err
+}
+
+// DeleteAttachment deletes actor's unlinked upload. It returns the upload
+// when no clone shares its object, for the caller to remove from storage.
+func (r *EventRepository) DeleteAttachment(id, actor uuid.UUID) ([]models.Attachment, error) {
+	var orphans []models.Attachment
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var a models.Attachment
+		if err := tx.First(&a, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if a.EventID != nil || a.UploadedBy != actor {
+			return ErrAttachmentUnavailable
+		}
+
+		deleted, err := deleteUnlinked(tx, []models.Attachment{a})
+		if err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			// linked to an event meanwhile
+			return ErrAttachmentUnavailable
+		}
+		orphans, err = unsharedObjects(tx, deleted)
+		return err
+	})
+	return orphans, err
+}
+
+// StaleUploads returns uploads still unlinked that were made before cutoff.
+// On an unscoped repository that is across all tenants.
+func (r *EventRepository) StaleUploads(cutoff time.Time, limit int) ([]models.Attachment, error) {
+	var rows []models.Attachment
+	err := r.DB.Where("event_id IS NULL AND created_at < ?", cutoff).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&rows).Error
+	return rows, err
+}
+
+// DeleteUnlinkedAttachments deletes those of rows that are still unlinked
+// and returns the ones whose object no other row shares.
+func (r *EventRepository) DeleteUnlinkedAttachments(rows []models.Attachment) ([]models.Attachment, error) {
+	var orphans []models.Attachment
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		deleted, err := deleteUnlinked(tx, rows)
+		if err != nil {
+			return err
+		}
+		orphans, err = unsharedObjects(tx, deleted)
+		return err
+	})
+	return orphans, err
+}
+
+// deleteUnlinked deletes each row unless it has been linked to an event
+// since it was read, and returns the rows it deleted.
+func deleteUnlinked(tx *gorm.DB, rows []models.Attachment) ([]models.Attachment, error) {
+	var deleted []models.Attachment
+	for _, a := range rows {
+		res := tx.Where("id = ? AND event_id IS NULL", a.ID).Delete(&models.Attachment{})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected > 0 {
+			deleted = append(deleted, a)
+		}
+	}
+	return deleted, nil
+}
+
+// unsharedObjects picks, one per object, the deleted rows whose stored
+// object no remaining row uses.
+func unsharedObjects(tx *gorm.DB, deleted []models.Attachment) ([]models.Attachment, error) {
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(deleted))
+	for _, a := range deleted {
+		keys = append(keys, a.StorageKey)
+	}
+
+	// keys are unique across tenants
+	var shared []string
+	if err := tenant.Unscoped(tx).Model(&models.Attachment{}).
+		Where("storage_key IN ?", keys).
+		Distinct().
+		Pluck("storage_key", &shared).Error; err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]bool, len(shared)+len(deleted))
+	for _, k := range shared {
+		skip[k] = true
+	}
+	var orphans []models.Attachment
+	for _, a := range deleted {
+		if !skip[a.StorageKey] {
+			skip[a.StorageKey] = true
+			orphans = append(orphans, a)
+		}
+	}
+	return orphans, nil
+}
+
+// CreateEventWithAttachments creates the event and links the uploads to it
+// in one transaction.
+func (r *EventRepository) CreateEventWithAttachments(event *models.Event, body *models.AnnouncementBody, tags []models.EventTag, attachmentIDs []uuid.UUID, actor uuid.UUID) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := (&EventRepository{DB: tx}).CreateEvent(event, body, tags); err != nil {
+			return err
+		}
+		return linkAttachments(tx, event.ID, attachmentIDs, actor)
+	})
+}
+
+// SetEventAttachments makes attachmentIDs the event's attachments. Uploads
+// that are dropped go back to being unlinked.
+func (r *EventRepository) SetEventAttachments(eventID uuid.UUID, attachmentIDs []uuid.UUID, actor uuid.UUID) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := eventVisible(tx, eventID); err != nil {
+			return err
+		}
+		return linkAttachments(tx, eventID, attachmentIDs, actor)
+	})
+}
+
+// CopyEventAttachments gives a cloned event its own rows for the source's
+// attachments, and the copy of cover as its cover. The copies share the
+// stored objects.
+func (r *EventRepository) CopyEventAttachments(sourceID, eventID uuid.UUID, cover *uuid.UUID) error {
+	var src []models.Attachment
+	if err := r.DB.Where("event_id = ?", sourceID).Find(&src).Error; err != nil {
+		return err
+	}
+	if len(src) == 0 {
+		return nil
+	}
+
+	var newCover *uuid.UUID
+	rows := make([]models.Attachment, 0, len(src))
+	for _, a := range src {
+		oldID := a.ID
+		a.ID = uuid.New()
+		a.EventID = &eventID
+		a.CreatedAt = time.Time{}
+		if cover != nil && *cover == oldID {
+			newCover = &a.ID
+		}
+		rows = append(rows, a)
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+		if newCover == nil {
+			return nil
+		}
+		return tx.Model(&models.Event{}).
+			Where("id = ?", eventID).
+			Update("cover_attachment_id", *newCover).Error
+	})
+}
+
+// linkAttachments accepts attachments already on the event and unlinked
+// uploads made by actor; anything else fails the whole change.
+func linkAttachments(tx *gorm.DB, eventID uuid.UUID, ids []uuid.UUID, actor uuid.UUID) error {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	if len(unique) > 0 {
+		var rows []models.Attachment
+		if err := tx.Where("id IN ?", unique).Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) != len(unique) {
+			return ErrAttachmentUnavailable
+		}
+		for _, a := range rows {
+			if a.EventID != nil && *a.EventID != eventID {
+				return ErrAttachmentUnavailable
+			}
+			if a.EventID == nil && a.UploadedBy != actor {
+				return ErrAttachmentUnavailable
+			}
+		}
+	}
+
+	drop := tx.Model(&models.Attachment{}).Where("event_id = ?", eventID)
+	uncover := tx.Model(&models.Event{}).Where("id = ? AND cover_attachment_id IS NOT NULL", eventID)
+	if len(unique) > 0 {
+		drop = drop.Where("id NOT IN ?", unique)
+		uncover = uncover.Where("cover_attachment_id NOT IN ?", unique)
+	}
+	if err := drop.Update("event_id", nil).Error; err != nil {
+		return err
+	}
+	// a dropped attachment cannot stay the cover
+	if err := uncover.Update("cover_attachment_id", nil).Error; err != nil {
+		return err
+	}
+
+	if len(unique) == 0 {
+		return nil
+	}
+	return tx.Model(&models.Attachment{}).
+		Where("id IN ?", unique).
+		Update("event_id", eventID).Error
+}
+
+// PendingScans returns the oldest attachments still awaiting a verdict
+// whose retry is due. On an unscoped repository that is across all tenants.
+func (r *EventRepository) PendingScans(limit int, now time.Time) ([]models.Attachment, error) {
+	var rows []models.Attachment
+	err := r.DB.Where("scan_status = ?", models.ScanPending).
+		Where("next_scan_at IS NULL OR next_scan_at <= ?", now).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&rows).Error
+	return rows, err
+}
+
+// SetAttachmentScan records a verdict for every row sharing the object, so
+// clones made while the scan was pending are released together.
+func (r *EventRepository) SetAttachmentScan(storageKey, status, signature string, at time.Time) error {
+	return r.DB.Model(&models.Attachment{}).
+		Where("storage_key = ?", storageKey).
+		Updates(map[string]interface{}{
+			"scan_status":    status,
+			"scan_signature": signature,
+			"scanned_at":     at,
+		}).Error
+}
+
+// SetAttachmentScanFailure records a failed attempt for every row sharing
+// the object, with status ScanPending to retry at next or ScanError to stop.
+func (r *EventRepository) SetAttachmentScanFailure(storageKey, status string, attempts int, next time.Time) error {
+	return r.DB.Model(&models.Attachment{}).
+		Where("storage_key = ?", storageKey).
+		Updates(map[string]interface{}{
+			"scan_status":   status,
+			"scan_attempts": attempts,
+			"next_scan_at":  next,
+		}).Error
+}
+
+// PendingVariants returns clean images still waiting for their renditions.
+// On an unscoped repository that is across all tenants.
+func (r *EventRepository) PendingVariants(limit int) ([]models.Attachment, error) {
+	var rows []models.Attachment
+	err := r.DB.Where("needs_variants AND scan_status = ?", models.ScanClean).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&rows).Error
+	return rows, err
+}
+
+// SetAttachmentVariants records the renditions, possibly none, for every
+// row sharing the object and marks them done.
+func (r *EventRepository) SetAttachmentVariants(storageKey string, variants []models.ImageVariant) error {
+	var total int64
+	for _, v := range variants {
+		total += v.Size
+	}
+	return r.DB.Model(&models.Attachment{}).
+		Where("storage_key = ?", storageKey).
+		Updates(map[string]interface{}{
+			"variants":       datatypes.NewJSONSlice(variants),
+			"variant_bytes":  total,
+			"needs_variants": false,
+		}).Error
+}
+
+// SetEventCover sets or, with nil, clears the event's cover.
+func (r *EventRepository) SetEventCover(eventID uuid.UUID, coverID *uuid.UUID) error {
+	res := r.DB.Model(&models.Event{}).
+		Where("id = ?", eventID).
+		Update("cover_attachment_id", coverID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetPublishedCover returns an attachment only while it is the clean cover
+// of an approved event, the one case in which it may be served without a
+// login.
+func (r *EventRepository) GetPublishedCover(id uuid.UUID) (*models.Attachment, error) {
+	var a models.Attachment
+	err := r.DB.
+		Where("id = ? AND scan_status = ?", id, models.ScanClean).
+		Where("EXISTS (SELECT 1 FROM events WHERE events.cover_attachment_id = attachments.id AND events.status = ?)", "approved").
+		First(&a).Error
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"strings"
+
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Viewer is who a feed is being built for. SeeAll skips audience filtering
+// for moderators and admins.
+type Viewer struct {
+	UserID  uuid.UUID
+	Profile models.AppUser
+	SeeAll  bool
+}
+
+// audienceUsers selects the tenant's staff directory entries in a.
+func (r *EventRepository) audienceUsers(a models.Audience) (*gorm.DB, error) {
+	// raw tenant check: an unscoped repository would reach every tenant's staff
+	if _, err := r.tenantID(); err != nil {
+		return nil, err
+	}
+
+	q := r.DB.Model(&models.AppUser{})
+	if a.Everyone() {
+		return q, nil
+	}
+
+	var parts []string
+	var args []any
+
+	if a.HasDimensions() {
+		var dims []string
+		for _, d := range []struct {
+			column string
+			values []string
+		}{{"department", a.Departments}, {"location", a.Locations}, {"job_role", a.Roles}} {
+			if len(d.values) > 0 {
+				dims = append(dims, "LOWER("+d.column+") IN ?")
+				args = append(args, d.values)
+			}
+		}
+		parts = append(parts, "("+strings.Join(dims, " AND ")+")")
+	}
+	if len(a.UserIDs) > 0 {
+		parts = append(parts, "id IN ?")
+		args = append(args, a.UserIDs)
+	}
+
+	return q.Where("("+strings.Join(parts, " OR ")+")", args...), nil
+}
+
+// FetchAudienceEmails lists the email addresses of everyone in a.
+func (r *EventRepository) FetchAudienceEmails(a models.Audience) ([]string, error) {
+	q, err := r.audienceUsers(a)
+	if err != nil {
+		return nil, err
+	}
+	var emails []string
+	err = q.Where("email <> ''").Pluck("email", &emails).Error
+	return emails, err
+}
+
+// CountAudience counts the staff in a.
+func (r *EventRepository) CountAudience(a models.Audience) (int64, error) {
+	q, err := r.audienceUsers(a)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	err = q.Count(&n).Error
+	return n, err
+}
+
+func (r *EventRepository) GetAppUser(id uuid.UUID) (*models.AppUser, error) {
+	var u models.AppUser
+	if err := r.DB.First(&u, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// ListAppUsers returns the directory entries of ids; unknown ids are skipped.
+func (r *EventRepository) ListAppUsers(ids []uuid.UUID) ([]models.AppUser, error) {
+	var users []models.AppUser
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.DB.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+// SetEventAudience replaces the event's audience; an empty audience means
+// everyone, which a struct Updates would skip as a zero value.
+func (r *EventRepository) SetEventAudience(eventID uuid.UUID, a models.Audience) error {
+	res := r.DB.Model(&models.Event{}).
+		Where("id = ?", eventID).
+		Update("audience", datatypes.NewJSONType(a))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// visibleTo limits an events query to those v may see: events for
+// everyone, events naming v, events whose dimensions all match v's
+// profile, and v's own events.
+func visibleTo(q *gorm.DB, v *Viewer) *gorm.DB {
+	if v == nil || v.SeeAll {
+		return q
+	}
+
+	length, contains := audienceSQL(q.Dialector.Name())
+	dims := length("departments") + " + " + length("locations") + " + " + length("roles")
+	matches := func(key, param string) string {
+		return "(" + length(key) + " = 0 OR " + contains(key, param) + ")"
+	}
+
+	p := v.Profile
+	return q.Where(`(
+		created_by = @user
+		OR `+contains("user_ids", "@user")+`
+		OR (`+dims+` = 0 AND `+length("user_ids")+` = 0)
+		OR (`+dims+` > 0
+			AND `+matches("departments", "@department")+`
+			AND `+matches("locations", "@location")+`
+			AND `+matches("roles", "@role")+`)
+	)`, map[string]any{
+		"user":       v.UserID.String(),
+		"department": strings.ToLower(strings.TrimSpace(p.Department)),
+		"location":   strings.ToLower(strings.TrimSpace(p.Location)),
+		"role":       strings.ToLower(strings.TrimSpace(p.JobRole)),
+	})
+}
+
+// audienceSQL spells the audience's JSON array tests for the dialect:
+// jsonb in production, JSON1 for the sqlite the tests run on.
+func audienceSQL(dialect string) (length func(key string) string, contains func(key, param string) string) {
+	if dialect == "sqlite" {
+		length = func(key string) string {
+			return "COALESCE(json_array_length(audience, '$." + key + "'), 0)"
+		}
+		contains = func(key, param string) string {
+			return "EXISTS (SELECT 1 FROM json_each(audience, '$." + key + "') WHERE value = " + param + ")"
+		}
+		return length, contains
+	}
+
+	length = func(key string) string {
+		return "COALESCE(jsonb_array_length(audience->'" + key + "'), 0)"
+	}
+	contains = func(key, param string) string {
+		return "COALESCE(audience->'" + key + "', '[]'::jsonb) @> jsonb_build_array(CAST(" + param + " AS text))"
+	}
+	return length, contains
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"events-service/internal/events/models"
+	"events-service/internal/tenant"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// publishAuditLockKey serialises writers of the publish_audit chain.
+const publishAuditLockKey = 7_301_001
+
+// PublishAuditHashVersion is the hash new rows are chained with. Version 1
+// predates tenants; version 2 also covers the row's tenant.
+const PublishAuditHashVersion = 2
+
+var errChainBroken = errors.New("publish audit chain broken")
+
+// PublishAuditHash chains a row to the hash of the row before it, with the
+// fields of the row's hash version. Details are re-encoded so Postgres'
+// jsonb normalisation does not change the hash.
+func PublishAuditHash(prevHash string, a models.PublishAudit) string {
+	details := ""
+	if len(a.Details) > 0 {
+		var v any
+		if err := json.Unmarshal(a.Details, &v); err == nil {
+			b, _ := json.Marshal(v)
+			details = string(b)
+		} else {
+			details = string(a.Details)
+		}
+	}
+
+	fields := []string{
+		prevHash,
+		a.EventID.String(),
+		a.Channel,
+		a.Status,
+		details,
+		a.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if a.HashVersion >= 2 {
+		fields = append(fields, "v"+strconv.Itoa(a.HashVersion), a.TenantID.String())
+	}
+
+	h := sha256.New()
+	h.Write([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// appendPublishAudit links the row to the current chain head, inserts it
+// and moves publish_audit_head on to it. Callers pass their transaction so
+// the row commits with the change it records.
+func appendPublishAudit(tx *gorm.DB, audit *models.PublishAudit) error {
+	if tx.Dialector.Name() == "postgres" {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", publishAuditLockKey).Error; err != nil {
+			return err
+		}
+	}
+
+	// one chain across all tenants
+	var head models.PublishAudit
+	err := tenant.Unscoped(tx).Where("hash IS NOT NULL AND hash <> ''").
+		Order("id DESC").
+		Limit(1).
+		Find(&head).Error
+	if err != nil {
+		return err
+	}
+
+	if audit.CreatedAt.IsZero() {
+		audit.CreatedAt = time.Now()
+	}
+	// Postgres keeps microseconds; hash what will be read back
+	audit.CreatedAt = audit.CreatedAt.Truncate(time.Microsecond)
+	// the create callback stamps the tenant too late for the hash
+	if id, ok := tenant.IDFrom(tx.Statement.Context); ok {
+		audit.TenantID = id
+	}
+	audit.HashVersion = PublishAuditHashVersion
+	audit.PrevHash = head.Hash
+	audit.Hash = PublishAuditHash(audit.PrevHash, *audit)
+
+	if err := tx.Create(audit).Error; err != nil {
+		return err
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"hash":       audit.Hash,
+			"chained":    gorm.Expr("publish_audit_head.chained + 1"),
+			"updated_at": audit.CreatedAt,
+		}),
+	}).Create(&models.PublishAuditHead{ID: 1, Hash: audit.Hash, Chained: 1, UpdatedAt: audit.CreatedAt}).Error
+}
+
+// ChainBreak is the first row whose stored hashes do not match.
+type ChainBreak struct {
+	ID     uint   `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// ChainReport is the result of walking the publish_audit chain.
+type ChainReport struct {
+	Checked   int         `json:"checked"`
+	Unchained int         `json:"unchained"` // rows written before chaining was enabled
+	Valid     bool        `json:"valid"`
+	Break     *ChainBreak `json:"break,omitempty"`
+}
+
+// chainVerifier walks rows in id order, carrying the previous hash and
+// hash version.
+type chainVerifier struct {
+	report  ChainReport
+	prev    string
+	version int
+	last    uint
+	started bool
+}
+
+// check consumes one row and reports false once a break is found.
+func (v *chainVerifier) check(a models.PublishAudit) bool {
+	if a.Hash == "" {
+		if v.started {
+			v.report.Break = &ChainBreak{ID: a.ID, Reason: "row has no hash"}
+			return false
+		}
+		v.report.Unchained++
+		return true
+	}
+
+	v.report.Checked++
+
+	if v.started && a.PrevHash != v.prev {
+		v.report.Break = &ChainBreak{ID: a.ID, Reason: "prev_hash does not match previous row"}
+		return false
+	}
+	if !v.started && a.PrevHash != "" {
+		v.report.Break = &ChainBreak{ID: a.ID, Reason: "first chained row does not start the chain"}
+		return false
+	}
+	// a row cannot step back to a version that hashes less of it
+	if max(a.HashVersion, 1) < v.version {
+		v.report.Break = &ChainBreak{ID: a.ID, Reason: "row uses an older hash version than the row before it"}
+		return false
+	}
+	if PublishAuditHash(a.PrevHash, a) != a.Hash {
+		v.report.Break = &ChainBreak{ID: a.ID, Reason: "hash does not match row contents"}
+		return false
+	}
+
+	v.started = true
+	v.prev = a.Hash
+	v.version = max(a.HashVersion, 1)
+	v.last = a.ID
+	return true
+}
+
+// finish compares where the walk ended with the recorded head, nil when
+// none was ever recorded. Rows deleted from the end only show here.
+func (v *chainVerifier) finish(head *models.PublishAuditHead) {
+	if v.report.Break == nil {
+		var chained int64
+		var hash string
+		if head != nil {
+			chained, hash = head.Chained, head.Hash
+		}
+		switch {
+		case int64(v.report.Checked) < chained:
+			v.report.Break = &ChainBreak{ID: v.last, Reason: "chain ends before its recorded head"}
+		case int64(v.report.Checked) != chained || v.prev != hash:
+			v.report.Break = &ChainBreak{ID: v.last, Reason: "chain does not end at its recorded head"}
+		}
+	}
+	v.report.Valid = v.report.Break == nil
+}
+
+// VerifyChain checks a complete, id-ordered list of rows against the
+// recorded head.
+func VerifyChain(rows []models.PublishAudit, head *models.PublishAuditHead) ChainReport {
+	v := &chainVerifier{}
+	for _, a := range rows {
+		if !v.check(a) {
+			break
+		}
+	}
+	v.finish(head)
+	return v.report
+}
+
+// VerifyPublishAuditChain walks the whole publish_audit table in id order
+// and reports the first broken link, or that rows are missing from its end.
+func (r *EventRepository) VerifyPublishAuditChain() (*ChainReport, error) {
+	v := &chainVerifier{}
+
+	var batch []models.PublishAudit
+	err := tenant.Unscoped(r.DB).FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, a := range batch {
+			if !v.check(a) {
+				return errChainBroken
+			}
+		}
+		return nil
+	}).Error
+	if err != nil && err != errChainBroken {
+		return nil, err
+	}
+
+	var heads []models.PublishAuditHead
+	if err := r.DB.Limit(1).Find(&heads).Error; err != nil {
+		return nil, err
+	}
+	var head *models.PublishAuditHead
+	if len(heads) > 0 {
+		head = &heads[0]
+	}
+
+	v.finish(head)
+	return &v.report, nil
+}
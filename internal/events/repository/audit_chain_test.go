@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+func chain(t *testing.T, n int) []models.PublishAudit {
+	t.Helper()
+	return chainVersion(t, n, PublishAuditHashVersion)
+}
+
+// chainVersion builds n chained rows hashed with version.
+func chainVersion(t *testing.T, n, version int) []models.PublishAudit {
+	t.Helper()
+	eventID, tenantID := uuid.New(), uuid.New()
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	rows := make([]models.PublishAudit, 0, n)
+	prev := ""
+	for i := 0; i < n; i++ {
+		a := models.PublishAudit{
+			ID:          uint(i + 1),
+			TenantID:    tenantID,
+			EventID:     eventID,
+			Channel:     "email",
+			Status:      "sent",
+			Details:     datatypes.JSON(`{"kind": "announcement", "note": "delivered"}`),
+			CreatedAt:   base.Add(time.Duration(i) * time.Minute),
+			PrevHash:    prev,
+			HashVersion: version,
+		}
+		a.Hash = PublishAuditHash(prev, a)
+		prev = a.Hash
+		rows = append(rows, a)
+	}
+	return rows
+}
+
+// headOf is the head appendPublishAudit would have recorded for rows.
+func headOf(rows []models.PublishAudit) *models.PublishAuditHead {
+	head := &models.PublishAuditHead{ID: 1}
+	for _, a := range rows {
+		if a.Hash != "" {
+			head.Hash = a.Hash
+			head.Chained++
+		}
+	}
+	return head
+}
+
+func TestVerifyChainValid(t *testing.T) {
+	rows := chain(t, 4)
+	report := VerifyChain(rows, headOf(rows))
+	if !report.Valid || report.Checked != 4 || report.Break != nil {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestVerifyChainDetectsEditedRow(t *testing.T) {
+	rows := chain(t, 4)
+	rows[2].Status = "failed"
+
+	report := VerifyChain(rows, headOf(rows))
+	if report.Valid || report.Break == nil || report.Break.ID != 3 {
+		t.Fatalf("expected break at row 3, got %+v", report)
+	}
+}
+
+func TestVerifyChainDetectsMovedTenant(t *testing.T) {
+	rows := chain(t, 3)
+	rows[1].TenantID = uuid.New()
+
+	report := VerifyChain(rows, headOf(rows))
+	if report.Valid || report.Break == nil || report.Break.ID != 2 {
+		t.Fatalf("expected break at row 2, got %+v", report)
+	}
+}
+
+func TestVerifyChainHashVersions(t *testing.T) {
+	// rows chained before tenants existed were given one afterwards
+	rows := chainVersion(t, 3, 1)
+	for i := range rows {
+		rows[i].TenantID = uuid.New()
+	}
+	next := models.PublishAudit{ID: 4, TenantID: uuid.New(), EventID: uuid.New(), Channel: "email", Status: "sent",
+		PrevHash: rows[2].Hash, HashVersion: PublishAuditHashVersion}
+	next.Hash = PublishAuditHash(next.PrevHash, next)
+	rows = append(rows, next)
+
+	if report := VerifyChain(rows, headOf(rows)); !report.Valid || report.Checked != 4 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	// a version 2 row rewritten as version 1 no longer covers its tenant
+	downgraded := next
+	downgraded.ID, downgraded.PrevHash, downgraded.HashVersion = 5, next.Hash, 1
+	downgraded.Hash = PublishAuditHash(downgraded.PrevHash, downgraded)
+	rows = append(rows, downgraded)
+	if report := VerifyChain(rows, headOf(rows)); report.Valid || report.Break.ID != 5 {
+		t.Fatalf("expected break at row 5, got %+v", report)
+	}
+}
+
+func TestVerifyChainDetectsDeletedRow(t *testing.T) {
+	rows := chain(t, 4)
+	rows = append(rows[:1], rows[2:]...)
+
+	report := VerifyChain(rows, headOf(rows))
+	if report.Valid || report.Break == nil || report.Break.ID != 3 {
+		t.Fatalf("expected break at row 3, got %+v", report)
+	}
+}
+
+func TestVerifyChainDetectsTruncatedTail(t *testing.T) {
+	rows := chain(t, 4)
+	head := headOf(rows)
+
+	report := VerifyChain(rows[:2], head)
+	if report.Valid || report.Break == nil || report.Break.ID != 2 {
+		t.Fatalf("expected break after row 2, got %+v", report)
+	}
+	if report := VerifyChain(nil, head); report.Valid {
+		t.Fatalf("every row deleted: %+v", report)
+	}
+
+	// a head that was never recorded only fits an empty chain
+	if report := VerifyChain(rows, nil); report.Valid {
+		t.Fatalf("chain without a head: %+v", report)
+	}
+	if report := VerifyChain(nil, nil); !report.Valid {
+		t.Fatalf("empty chain: %+v", report)
+	}
+}
+
+func TestVerifyChainSkipsLegacyRows(t *testing.T) {
+	legacy := models.PublishAudit{ID: 1, EventID: uuid.New(), Channel: "moderation", Status: "approved"}
+	rows := append([]models.PublishAudit{legacy}, chain(t, 2)...)
+
+	report := VerifyChain(rows, headOf(rows))
+	if !report.Valid || report.Unchained != 1 || report.Checked != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	// an unhashed row after the chain started is a break
+	rows = append(rows, models.PublishAudit{ID: 9, EventID: uuid.New()})
+	if report := VerifyChain(rows, headOf(rows)); report.Valid || report.Break.ID != 9 {
+		t.Fatalf("expected break at row 9, got %+v", report)
+	}
+}
+
+func TestPublishAuditHashIgnoresJSONFormatting(t *testing.T) {
+	a := chain(t, 1)[0]
+	b := a
+	b.Details = datatypes.JSON(`{"note":"delivered","kind":"announcement"}`)
+	if PublishAuditHash("", a) != PublishAuditHash("", b) {
+		t.Fatal("hash changed with JSON key order or spacing")
+	}
+}
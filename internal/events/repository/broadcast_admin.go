@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Admin actions on broadcast jobs.
+const (
+	BroadcastRetry  = "retry"
+	BroadcastCancel = "cancel"
+	BroadcastPurge  = "purge"
+)
+
+// broadcastActionFrom lists the job states each action applies to. Jobs
+// being processed are left to the worker.
+var broadcastActionFrom = map[string][]string{
+	BroadcastRetry:  {"failed", "cancelled"},
+	BroadcastCancel: {"pending"},
+	BroadcastPurge:  {"failed", "cancelled", "sent"},
+}
+
+var ErrBroadcastActionNotAllowed = errors.New("action not allowed in the job's current status")
+
+type BroadcastFilter struct {
+	Status  string
+	Channel string
+	EventID *uuid.UUID
+	From    *time.Time
+	To      *time.Time
+}
+
+func (r *EventRepository) RecordBroadcastAttempt(a *models.BroadcastAttempt) error {
+	return r.DB.Create(a).Error
+}
+
+// ListBroadcasts returns matching jobs, most recently updated first. A size
+// of 0 returns every match.
+func (r *EventRepository) ListBroadcasts(f BroadcastFilter, page, size int) ([]models.BroadcastQueue, int64, error) {
+	var jobs []models.BroadcastQueue
+	var total int64
+
+	q := r.DB.Model(&models.BroadcastQueue{})
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
+	}
+	if f.Channel != "" {
+		q = q.Where("channel = ?", f.Channel)
+	}
+	if f.EventID != nil {
+		q = q.Where("event_id = ?", *f.EventID)
+	}
+	if f.From != nil {
+		q = q.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("created_at < ?", *f.To)
+	}
+
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	q = q.Order("updated_at DESC, id DESC")
+	if size > 0 {
+		q = q.Offset((page - 1) * size).Limit(size)
+	}
+	if err := q.Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+func (r *EventRepository) GetBroadcast(id int) (*models.BroadcastQueue, error) {
+	var job models.BroadcastQueue
+	if err := r.DB.First(&job, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListBroadcastAttempts returns a job's failed attempts, oldest first.
+func (r *EventRepository) ListBroadcastAttempts(jobID int) ([]models.BroadcastAttempt, error) {
+	var attempts []models.BroadcastAttempt
+	err := r.DB.Where("job_id = ?", jobID).Order("id ASC").Find(&attempts).Error
+	return attempts, err
+}
+
+// ApplyBroadcastAction retries, cancels or purges a job and records who did
+// it in the publish audit, in one transaction.
+func (r *EventRepository) ApplyBroadcastAction(id int, action string, actor uuid.UUID) error {
+	from, ok := broadcastActionFrom[action]
+	if !ok {
+		return fmt.Errorf("unknown broadcast action %q", action)
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var job models.BroadcastQueue
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&job, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if !contains(from, job.Status) {
+			return fmt.Errorf("%w: cannot %s a %s job", ErrBroadcastActionNotAllowed, action, job.Status)
+		}
+
+		var err error
+		switch action {
+		case BroadcastRetry:
+			// a fresh set of attempts; the history is kept
+			err = tx.Model(&job).Updates(map[string]any{
+				"status":          "pending",
+				"attempts":        0,
+				"next_attempt_at": nil,
+				"updated_at":      time.Now(),
+			}).Error
+		case BroadcastCancel:
+			msg := "cancelled by admin"
+			err = tx.Model(&job).Updates(map[string]any{
+				"status":          "cancelled",
+				"last_error":      &msg,
+				"next_attempt_at": nil,
+				"updated_at":      time.Now(),
+			}).Error
+		case BroadcastPurge:
+			if err = tx.Where("job_id = ?", job.ID).Delete(&models.BroadcastAttempt{}).Error; err == nil {
+				err = tx.Delete(&job).Error
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		details := map[string]any{
+			"job_id":          job.ID,
+			"action":          action,
+			"actor":           actor,
+			"previous_status": job.Status,
+			"attempts":        job.Attempts,
+		}
+		if job.LastError != nil {
+			details["last_error"] = *job.LastError
+		}
+		return appendPublishAudit(tx, &models.PublishAudit{
+			EventID: job.EventID,
+			Channel: job.Channel,
+			Status:  "admin_" + action,
+			Details: toJSON(details),
+		})
+	})
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
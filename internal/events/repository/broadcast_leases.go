@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"events-service/internal/events/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrLeaseLost means the job's lease ran out and it was reaped, possibly
+// already claimed by another worker; the caller must not touch it again.
+var ErrLeaseLost = errors.New("broadcast job lease lost")
+
+// updateLeased applies values to the jobs q selects, but only while owner
+// still holds their lease; a miss is ErrLeaseLost. An empty owner leaves
+// the update unguarded.
+func updateLeased(q *gorm.DB, owner string, values map[string]interface{}) error {
+	if owner == "" {
+		return q.Updates(values).Error
+	}
+	res := q.Where("status = ? AND lease_owner = ?", "processing", owner).Updates(values)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// ExtendBroadcastLease pushes the lease of a job owner is processing out to
+// lease from now.
+func (r *EventRepository) ExtendBroadcastLease(jobID int, owner string, lease time.Duration) error {
+	return updateLeased(r.DB.Model(&models.BroadcastQueue{}).Where("id = ?", jobID), owner, map[string]interface{}{
+		"lease_expires_at": gorm.Expr("now() + ? * interval '1 second'", lease.Seconds()),
+	})
+}
+
+// ReapExpiredBroadcasts takes over up to limit processing jobs whose lease
+// ran out, leasing them to owner so exactly one worker decides whether
+// each goes back to pending or fails. Attempts are not counted here.
+func (r *EventRepository) ReapExpiredBroadcasts(owner string, limit int, lease time.Duration) ([]models.BroadcastQueue, error) {
+	rows := []models.BroadcastQueue{}
+	err := r.DB.Raw(`
+		UPDATE broadcast_queue
+		SET lease_owner = ?, lease_expires_at = now() + ? * interval '1 second', updated_at = now()
+		WHERE id IN (
+		  SELECT id FROM broadcast_queue
+		  WHERE status = 'processing'
+		    AND lease_expires_at < now()
+		  ORDER BY lease_expires_at ASC
+		  LIMIT ?
+		  FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, owner, lease.Seconds(), limit).Scan(&rows).Error
+	return rows, err
+}
+
+// FailUnroutableBroadcasts fails the pending jobs on channels other than
+// channels, counting the attempt, and returns them.
+func (r *EventRepository) FailUnroutableBroadcasts(channels []string) ([]models.BroadcastQueue, error) {
+	rows := []models.BroadcastQueue{}
+	err := r.DB.Raw(`
+		UPDATE broadcast_queue
+		SET status = 'failed', attempts = attempts + 1,
+		    last_error = 'no notifier registered for channel ' || channel,
+		    next_attempt_at = NULL, updated_at = now()
+		WHERE status = 'pending' AND channel NOT IN ?
+		RETURNING *
+	`, channels).Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"events-service/internal/events/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r *EventRepository) ArchiveEvent(eventID uuid.UUID) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Event{}).
+			Where("id = ?", eventID).
+			Updates(map[string]interface{}{
+				"status":     "archived",
+				"updated_at": time.Now(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		audit := models.PublishAudit{
+			EventID:   eventID,
+			Channel:   "lifecycle",
+			Status:    "archived",
+			CreatedAt: time.Now(),
+		}
+		return appendPublishAudit(tx, &audit)
+	})
+}
+
+// DeleteEvent deletes the event with its attachment rows and returns the
+// attachments whose stored objects no other row uses.
+func (r *EventRepository) DeleteEvent(eventID uuid.UUID) ([]models.Attachment, error) {
+	var orphans []models.Attachment
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// broadcast_queue has no FK to events, so drop jobs that have not run yet
+		if err := tx.Where("event_id = ? AND status = ?", eventID, "pending").
+			Delete(&models.BroadcastQueue{}).Error; err != nil {
+			return err
+		}
+
+		var attachments []models.Attachment
+		if err := tx.Where("event_id = ?", eventID).Find(&attachments).Error; err != nil {
+			return err
+		}
+
+		res := tx.Where("id = ?", eventID).Delete(&models.Event{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// the FK cascades this on Postgres; not every database enforces it
+		if err := tx.Where("event_id = ?", eventID).Delete(&models.Attachment{}).Error; err != nil {
+			return err
+		}
+		var err error
+		orphans, err = unsharedObjects(tx, attachments)
+		return err
+	})
+	return orphans, err
+}
+
+// AddEventTags attaches tags the event does not already carry.
+func (r *EventRepository) AddEventTags(eventID uuid.UUID, tags []string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := eventVisible(tx, eventID); err != nil {
+			return err
+		}
+
+		var existing []string
+		if err := tx.Model(&models.EventTag{}).
+			Where("event_id = ?", eventID).
+			Pluck("tag", &existing).Error; err != nil {
+			return err
+		}
+
+		have := make(map[string]bool, len(existing))
+		for _, t := range existing {
+			have[t] = true
+		}
+
+		var rows []models.EventTag
+		for _, t := range tags {
+			if t == "" || have[t] {
+				continue
+			}
+			have[t] = true
+			rows = append(rows, models.EventTag{EventID: eventID, Tag: t})
+		}
+
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *EventRepository) RemoveEventTags(eventID uuid.UUID, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	if err := eventVisible(r.DB, eventID); err != nil {
+		return err
+	}
+	return r.DB.Where("event_id = ? AND tag IN ?", eventID, tags).
+		Delete(&models.EventTag{}).Error
+}
+
+// eventVisible guards writes to event child tables, which carry no
+// tenant_id of their own: the parent event must be in the caller's tenant.
+func eventVisible(db *gorm.DB, eventID uuid.UUID) error {
+	var n int64
+	if err := db.Model(&models.Event{}).Where("id = ?", eventID).Count(&n).Error; err != nil {
+		return err
+	}
+	if n == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
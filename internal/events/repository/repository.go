@@ -2,7 +2,9 @@ package repository
 
 import (
 	"encoding/json"
+	"errors"
 	"events-service/internal/events/models"
+	"events-service/internal/tenant"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +20,30 @@ func NewEventRepository(db *gorm.DB) *EventRepository {
 	return &EventRepository{DB: db}
 }
 
+// ForTenant returns a repository whose queries only see tenantID's rows and
+// whose inserts belong to it.
+func (r *EventRepository) ForTenant(tenantID uuid.UUID) *EventRepository {
+	return &EventRepository{DB: tenant.Scoped(r.DB, tenantID)}
+}
+
+// Transaction runs fn with a repository whose statements share one
+// transaction, keeping this repository's tenant scope.
+func (r *EventRepository) Transaction(fn func(repo *EventRepository) error) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(&EventRepository{DB: tx})
+	})
+}
+
+// tenantID is the tenant this repository is scoped to, for raw SQL the
+// GORM callbacks do not see.
+func (r *EventRepository) tenantID() (uuid.UUID, error) {
+	id, ok := tenant.IDFrom(r.DB.Statement.Context)
+	if !ok {
+		return uuid.Nil, tenant.ErrNoTenant
+	}
+	return id, nil
+}
+
 // Helper function to convert map to datatypes.JSON
 func toJSON(data map[string]interface{}) datatypes.JSON {
 	if data == nil {
@@ -55,6 +81,8 @@ func (r *EventRepository) GetEvent(id uuid.UUID) (*models.Event, error) {
 	err := r.DB.
 		Preload("Body").
 		Preload("Tags").
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Cover").
 		First(&event, "id = ?", id).Error
 
 	if err != nil {
@@ -64,7 +92,7 @@ func (r *EventRepository) GetEvent(id uuid.UUID) (*models.Event, error) {
 	return &event, nil
 }
 
-func (r *EventRepository) GetEventFeed(page int, size int, since *time.Time, channel string) ([]models.Event, int64, error) {
+func (r *EventRepository) GetEventFeed(page int, size int, since *time.Time, channel string, viewer *Viewer) ([]models.Event, int64, error) {
 	var events []models.Event
 	var total int64
 
@@ -72,12 +100,16 @@ func (r *EventRepository) GetEventFeed(page int, size int, since *time.Time, cha
 
 	q := r.DB.Model(&models.Event{}).
 		Preload("Tags").
+		Preload("Cover").
+		Where("status NOT IN ?", []string{"archived", "retracted"}).
 		Order("created_at DESC")
 
 	if since != nil {
 		q = q.Where("created_at > ?", *since)
 	}
 
+	q = visibleTo(q, viewer)
+
 	// Future feature: feed per channel (Teams, mobile, etc.)
 	if channel != "" {
 		_ = channel // placeholder for future filters
@@ -99,11 +131,15 @@ func (r *EventRepository) GetEventFeed(page int, size int, since *time.Time, cha
 func (r *EventRepository) UpdateEvent(event *models.Event, body *models.AnnouncementBody, tags []models.EventTag) error {
 	return r.DB.Transaction(func(tx *gorm.DB) error {
 
-		// Update event fields
-		if err := tx.Model(&models.Event{}).
+		// Update event fields; no rows means the event is another tenant's
+		res := tx.Model(&models.Event{}).
 			Where("id = ?", event.ID).
-			Updates(event).Error; err != nil {
-			return err
+			Updates(event)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
 		}
 
 		// Update body
@@ -130,16 +166,32 @@ func (r *EventRepository) UpdateEvent(event *models.Event, body *models.Announce
 	})
 }
 
+var ErrNotModeratable = errors.New("only pending or draft events can be moderated")
+
+// ModerateEvent approves or rejects an event awaiting moderation. Like
+// RetractEvent, the status check and change are one statement, so an event
+// that was already approved, rejected or retracted is left alone.
 func (r *EventRepository) ModerateEvent(eventID uuid.UUID, status string, moderatorID uuid.UUID, notes string) error {
 	return r.DB.Transaction(func(tx *gorm.DB) error {
 
 		// Update event status
-		if err := tx.Model(&models.Event{}).
-			Where("id = ?", eventID).
+		res := tx.Model(&models.Event{}).
+			Where("id = ? AND status IN ?", eventID, []string{"pending", "draft"}).
 			Updates(map[string]interface{}{
 				"status": status,
-			}).Error; err != nil {
-			return err
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			var n int64
+			if err := tx.Model(&models.Event{}).Where("id = ?", eventID).Count(&n).Error; err != nil {
+				return err
+			}
+			if n == 0 {
+				return gorm.ErrRecordNotFound
+			}
+			return ErrNotModeratable
 		}
 
 		// Insert audit entry - FIXED: convert map to datatypes.JSON
@@ -154,7 +206,7 @@ func (r *EventRepository) ModerateEvent(eventID uuid.UUID, status string, modera
 			CreatedAt: time.Now(),
 		}
 
-		if err := tx.Create(&audit).Error; err != nil {
+		if err := appendPublishAudit(tx, &audit); err != nil {
 			return err
 		}
 
@@ -165,8 +217,11 @@ func (r *EventRepository) ModerateEvent(eventID uuid.UUID, status string, modera
 func (r *EventRepository) GetFeedVersion() (int, error) {
 	var meta models.FeedMeta
 
-	// FeedMeta row should always be id=1
-	err := r.DB.First(&meta, "id = 1").Error
+	// one row per tenant, created by the first IncrementFeedVersion
+	err := r.DB.First(&meta).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
 	if err != nil {
 		return 0, err
 	}
@@ -175,11 +230,16 @@ func (r *EventRepository) GetFeedVersion() (int, error) {
 }
 
 func (r *EventRepository) IncrementFeedVersion() error {
+	tenantID, err := r.tenantID()
+	if err != nil {
+		return err
+	}
 	return r.DB.Exec(`
-        UPDATE feed_meta 
-        SET version = version + 1, updated_at = NOW()
-        WHERE id = 1
-    `).Error
+        INSERT INTO feed_meta (tenant_id, version, updated_at)
+        VALUES (?, 1, NOW())
+        ON CONFLICT (tenant_id)
+        DO UPDATE SET version = feed_meta.version + 1, updated_at = NOW()
+    `, tenantID).Error
 }
 
 // Enqueue a broadcast job for a given event and channel
@@ -194,8 +254,9 @@ func (r *EventRepository) EnqueueBroadcast(eventID uuid.UUID, channel string, pa
 	return r.DB.Create(&job).Error
 }
 
-// Fetch pending jobs up to a limit and mark them processing (returns rows)
-func (r *EventRepository) FetchPendingBroadcasts(limit int) ([]models.BroadcastQueue, error) {
+// Fetch pending jobs of a channel up to a limit, mark them processing and
+// lease them to owner for lease (returns rows)
+func (r *EventRepository) FetchPendingBroadcasts(owner string, channel string, limit int, lease time.Duration) ([]models.BroadcastQueue, error) {
 	// var jobs []models.BroadcastQueue
 
 	// Use FOR UPDATE SKIP LOCKED pattern via raw SQL to avoid races if you have multiple workers.
@@ -208,16 +269,18 @@ func (r *EventRepository) FetchPendingBroadcasts(limit int) ([]models.BroadcastQ
 	rows := []models.BroadcastQueue{}
 	err := tx.Raw(`
 		UPDATE broadcast_queue
-		SET status = 'processing', updated_at = now()
+		SET status = 'processing', lease_owner = ?, lease_expires_at = now() + ? * interval '1 second', updated_at = now()
 		WHERE id IN (
 		  SELECT id FROM broadcast_queue
 		  WHERE status = 'pending'
+		    AND channel = ?
+		    AND (next_attempt_at IS NULL OR next_attempt_at <= now())
 		  ORDER BY created_at ASC
 		  LIMIT ?
 		  FOR UPDATE SKIP LOCKED
 		)
 		RETURNING *
-	`, limit).Scan(&rows).Error
+	`, owner, lease.Seconds(), channel, limit).Scan(&rows).Error
 
 	if err != nil {
 		tx.Rollback()
@@ -230,16 +293,32 @@ func (r *EventRepository) FetchPendingBroadcasts(limit int) ([]models.BroadcastQ
 	return rows, nil
 }
 
-// Update job status after processing
-func (r *EventRepository) UpdateBroadcastJobStatus(jobID int, status string, attempts int, lastError *string) error {
-	return r.DB.Model(&models.BroadcastQueue{}).
-		Where("id = ?", jobID).
-		Updates(map[string]interface{}{
-			"status":     status,
-			"attempts":   attempts,
-			"last_error": lastError,
-			"updated_at": time.Now(),
-		}).Error
+// Update job status after processing. With an owner the update only
+// applies while that worker still holds the job's lease.
+func (r *EventRepository) UpdateBroadcastJobStatus(jobID int, owner string, status string, attempts int, lastError *string) error {
+	return updateLeased(r.DB.Model(&models.BroadcastQueue{}).Where("id = ?", jobID), owner, map[string]interface{}{
+		"status":           status,
+		"attempts":         attempts,
+		"last_error":       lastError,
+		"next_attempt_at":  nil,
+		"lease_owner":      nil,
+		"lease_expires_at": nil,
+		"updated_at":       time.Now(),
+	})
+}
+
+// ScheduleBroadcastRetry puts a failed job back in the queue, not to be
+// claimed before at. The owner guards it like UpdateBroadcastJobStatus.
+func (r *EventRepository) ScheduleBroadcastRetry(jobID int, owner string, attempts int, lastError string, at time.Time) error {
+	return updateLeased(r.DB.Model(&models.BroadcastQueue{}).Where("id = ?", jobID), owner, map[string]interface{}{
+		"status":           "pending",
+		"attempts":         attempts,
+		"last_error":       lastError,
+		"next_attempt_at":  at,
+		"lease_owner":      nil,
+		"lease_expires_at": nil,
+		"updated_at":       time.Now(),
+	})
 }
 
 func (r *EventRepository) CreatePublishAudit(eventID uuid.UUID, channel, status string, details map[string]any) error {
@@ -251,7 +330,9 @@ func (r *EventRepository) CreatePublishAudit(eventID uuid.UUID, channel, status
 		Details:   toJSON(details),
 		CreatedAt: time.Now(),
 	}
-	return r.DB.Create(&audit).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return appendPublishAudit(tx, &audit)
+	})
 }
 
 func (r *EventRepository) SearchGlobalTags(query string) ([]models.GlobalTag, error) {
@@ -270,7 +351,5 @@ func (r *EventRepository) SearchGlobalTags(query string) ([]models.GlobalTag, er
 }
 
 func (r *EventRepository) FetchActiveStaffEmails() ([]string, error) {
-    var emails []string
-    err := r.DB.Raw(`SELECT email FROM app_users`).Scan(&emails).Error
-    return emails, err
+    return r.FetchAudienceEmails(models.Audience{})
 }
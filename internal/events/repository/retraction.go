@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"events-service/internal/events/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrNotRetractable = errors.New("only approved events can be retracted")
+
+// RetractEvent marks an approved event retracted, cancels its pending
+// broadcast jobs and writes a retraction audit row. It returns the number of
+// cancelled jobs. The status check and change are one statement, so of two
+// concurrent retractions only one succeeds.
+func (r *EventRepository) RetractEvent(eventID uuid.UUID, reason string, retractedBy uuid.UUID) (int64, error) {
+	var cancelled int64
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Event{}).
+			Where("id = ? AND status = ?", eventID, "approved").
+			Updates(map[string]interface{}{
+				"status":     "retracted",
+				"updated_at": time.Now(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			var n int64
+			if err := tx.Model(&models.Event{}).Where("id = ?", eventID).Count(&n).Error; err != nil {
+				return err
+			}
+			if n == 0 {
+				return gorm.ErrRecordNotFound
+			}
+			return ErrNotRetractable
+		}
+
+		msg := "event retracted"
+		res = tx.Model(&models.BroadcastQueue{}).
+			Where("event_id = ? AND status = ?", eventID, "pending").
+			Updates(map[string]interface{}{
+				"status":     "cancelled",
+				"last_error": &msg,
+				"updated_at": time.Now(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		cancelled = res.RowsAffected
+
+		audit := models.PublishAudit{
+			EventID: eventID,
+			Channel: "retraction",
+			Status:  "retracted",
+			Details: toJSON(map[string]interface{}{
+				"reason":         reason,
+				"retracted_by":   retractedBy.String(),
+				"cancelled_jobs": cancelled,
+			}),
+			CreatedAt: time.Now(),
+		}
+		return appendPublishAudit(tx, &audit)
+	})
+
+	return cancelled, err
+}
+
+// DeliveredChannels lists the channels the event was successfully sent on.
+func (r *EventRepository) DeliveredChannels(eventID uuid.UUID) ([]string, error) {
+	var channels []string
+	err := r.DB.Model(&models.PublishAudit{}).
+		Where("event_id = ? AND status = ?", eventID, "sent").
+		Distinct("channel").
+		Order("channel ASC").
+		Pluck("channel", &channels).Error
+	return channels, err
+}
+
+// CancelIfRetracted cancels a claimed job whose event was retracted after
+// the job was queued, and reports whether it did. The owner guards the
+// update like UpdateBroadcastJobStatus.
+func (r *EventRepository) CancelIfRetracted(jobID int, eventID uuid.UUID, owner string) (bool, error) {
+	var n int64
+	err := r.DB.Model(&models.Event{}).Where("id = ? AND status = ?", eventID, "retracted").Count(&n).Error
+	if err != nil || n == 0 {
+		return false, err
+	}
+
+	msg := "event retracted"
+	err = updateLeased(r.DB.Model(&models.BroadcastQueue{}).Where("id = ?", jobID), owner, map[string]interface{}{
+		"status":           "cancelled",
+		"last_error":       &msg,
+		"next_attempt_at":  nil,
+		"lease_owner":      nil,
+		"lease_expires_at": nil,
+		"updated_at":       time.Now(),
+	})
+	return err == nil, err
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"time"
+
+	"events-service/internal/events/models"
+
+	"gorm.io/gorm"
+)
+
+// ActiveSigningKey returns the key new links are signed with.
+func (r *EventRepository) ActiveSigningKey() (*models.SigningKey, error) {
+	var key models.SigningKey
+	err := r.DB.Where("retired_at IS NULL").Order("created_at DESC").First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetSigningKey returns a key that may still verify links at now.
+func (r *EventRepository) GetSigningKey(id string, now time.Time) (*models.SigningKey, error) {
+	var key models.SigningKey
+	err := r.DB.
+		Where("id = ? AND (expires_at IS NULL OR expires_at > ?)", id, now).
+		First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *EventRepository) ListSigningKeys() ([]models.SigningKey, error) {
+	var keys []models.SigningKey
+	if err := r.DB.Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *EventRepository) CreateSigningKey(key *models.SigningKey) error {
+	return r.DB.Create(key).Error
+}
+
+// RotateSigningKey makes key the active one. The keys it replaces keep
+// verifying for grace, the longest a link may live; keys past that are
+// deleted.
+func (r *EventRepository) RotateSigningKey(key *models.SigningKey, grace time.Duration) error {
+	now := time.Now()
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at IS NOT NULL AND expires_at <= ?", now).
+			Delete(&models.SigningKey{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SigningKey{}).
+			Where("retired_at IS NULL").
+			Updates(map[string]any{"retired_at": now, "expires_at": now.Add(grace)}).Error; err != nil {
+			return err
+		}
+		return tx.Create(key).Error
+	})
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r *EventRepository) CreateTemplate(tpl *models.EventTemplate) error {
+	return r.DB.Create(tpl).Error
+}
+
+func (r *EventRepository) GetTemplate(id uuid.UUID) (*models.EventTemplate, error) {
+	var tpl models.EventTemplate
+	if err := r.DB.First(&tpl, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &tpl, nil
+}
+
+func (r *EventRepository) ListTemplates() ([]models.EventTemplate, error) {
+	var templates []models.EventTemplate
+	if err := r.DB.Order("name ASC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *EventRepository) UpdateTemplate(tpl *models.EventTemplate) error {
+	return r.DB.Save(tpl).Error
+}
+
+func (r *EventRepository) DeleteTemplate(id uuid.UUID) error {
+	res := r.DB.Where("id = ?", id).Delete(&models.EventTemplate{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+)
+
+func (r *EventRepository) GetTenant(id uuid.UUID) (*models.Tenant, error) {
+	var t models.Tenant
+	if err := r.DB.First(&t, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *EventRepository) GetTenantByHost(host string) (*models.Tenant, error) {
+	var t models.Tenant
+	if err := r.DB.First(&t, "host = ?", host).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
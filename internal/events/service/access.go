@@ -0,0 +1,32 @@
+package service
+
+import (
+	"time"
+
+	"events-service/internal/auth"
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+)
+
+// UserRoles implements auth.RoleStore from the tenant's role table.
+func (s *EventService) UserRoles(tenantID, userID uuid.UUID) ([]string, error) {
+	return s.Repo.ForTenant(tenantID).GetUserRoles(userID)
+}
+
+// RecordAccessDenied implements auth.DenialRecorder.
+func (s *EventService) RecordAccessDenied(d auth.AccessDenial) error {
+	entry := models.AccessAudit{
+		Method:     d.Method,
+		Route:      d.Route,
+		Permission: d.Permission,
+		Reason:     d.Reason,
+		ClientIP:   d.ClientIP,
+		CreatedAt:  time.Now(),
+	}
+	if d.ActorID != uuid.Nil {
+		actor := d.ActorID
+		entry.ActorID = &actor
+	}
+	return s.Repo.CreateAccessAudit(&entry)
+}
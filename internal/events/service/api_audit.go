@@ -0,0 +1,73 @@
+package service
+
+import (
+	"encoding/json"
+
+	"events-service/internal/audit"
+	"events-service/internal/events/models"
+	"events-service/internal/events/repository"
+
+	"github.com/google/uuid"
+)
+
+// RecordAPIAudit implements audit.Recorder, filing the entry under its
+// tenant.
+func (s *EventService) RecordAPIAudit(e audit.Entry) error {
+	entry := models.APIAudit{
+		RequestID:     e.RequestID,
+		ActorKind:     e.ActorKind,
+		Method:        e.Method,
+		Route:         e.Route,
+		Path:          e.Path,
+		TargetType:    e.TargetType,
+		TargetID:      e.TargetID,
+		StatusCode:    e.StatusCode,
+		ClientIP:      e.ClientIP,
+		RequestDigest: e.RequestDigest,
+		BeforeDigest:  e.BeforeDigest,
+		AfterDigest:   e.AfterDigest,
+		CreatedAt:     e.CreatedAt,
+	}
+	if e.ActorID != uuid.Nil {
+		actor := e.ActorID
+		entry.ActorID = &actor
+	}
+	return s.ForTenant(e.TenantID).Repo.CreateAPIAudit(&entry)
+}
+
+// Snapshot implements audit.Snapshotter for events and templates.
+func (s *EventService) Snapshot(tenantID uuid.UUID, targetType, targetID string) ([]byte, bool) {
+	id, err := uuid.Parse(targetID)
+	if err != nil {
+		return nil, false
+	}
+	repo := s.Repo.ForTenant(tenantID)
+
+	var state any
+	switch targetType {
+	case "event":
+		state, err = repo.GetEvent(id)
+	case "template":
+		state, err = repo.GetTemplate(id)
+	default:
+		return nil, false
+	}
+	if err != nil {
+		return nil, false
+	}
+
+	b, err := json.Marshal(state)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+func (s *EventService) ListAPIAudit(f repository.APIAuditFilter, page, size int) ([]models.APIAudit, int64, error) {
+	return s.Repo.ListAPIAudit(f, page, size)
+}
+
+// VerifyPublishAuditChain walks the publish_audit hash chain.
+func (s *EventService) VerifyPublishAuditChain() (*repository.ChainReport, error) {
+	return s.Repo.VerifyPublishAuditChain()
+}
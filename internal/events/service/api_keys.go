@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"events-service/internal/auth"
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidAPIKeyRequest = errors.New("invalid api key request")
+
+// IssueAPIKey creates a key with the given scopes and returns the stored row
+// together with the plaintext key, which cannot be recovered later.
+func (s *EventService) IssueAPIKey(name string, scopes []string, expiresAt time.Time, createdBy uuid.UUID) (*models.APIKey, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", ErrInvalidAPIKeyRequest)
+	}
+	for _, sc := range scopes {
+		if !auth.APIKeyScopes[sc] {
+			return nil, "", fmt.Errorf("%w: unknown scope %q", ErrInvalidAPIKeyRequest, sc)
+		}
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("%w: expires_at must be in the future", ErrInvalidAPIKeyRequest)
+	}
+
+	plaintext, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := models.APIKey{
+		ID:        uuid.New(),
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedBy: createdBy,
+	}
+	if err := s.Repo.CreateAPIKey(&key); err != nil {
+		return nil, "", err
+	}
+
+	return &key, plaintext, nil
+}
+
+func (s *EventService) ListAPIKeys() ([]models.APIKey, error) {
+	return s.Repo.ListAPIKeys()
+}
+
+func (s *EventService) RevokeAPIKey(id uuid.UUID) error {
+	return s.Repo.RevokeAPIKey(id)
+}
+
+// LookupAPIKey implements auth.APIKeyStore. Prefixes are unique across
+// tenants, so this runs unscoped; the record names the key's tenant.
+func (s *EventService) LookupAPIKey(prefix string) (*auth.APIKeyRecord, error) {
+	key, err := s.Repo.GetAPIKeyByPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &auth.APIKeyRecord{
+		ID:         key.ID,
+		TenantID:   key.TenantID,
+		Hash:       key.KeyHash,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}, nil
+}
+
+// TouchAPIKey implements auth.APIKeyStore.
+func (s *EventService) TouchAPIKey(id uuid.UUID, at time.Time) error {
+	return s.Repo.TouchAPIKey(id, at)
+}
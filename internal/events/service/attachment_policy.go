@@ -0,0 +1,158 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// Codes carried by AttachmentError, for clients to key messages on.
+const (
+	CodeFileRequired          = "file_required"
+	CodeFileTooLarge          = "file_too_large"
+	CodeTypeNotAllowed        = "type_not_allowed"
+	CodeQuotaExceeded         = "quota_exceeded"
+	CodeEventAttachmentsLimit = "event_attachments_too_large"
+	CodeAttachmentUnavailable = "attachment_unavailable"
+	CodeAttachmentInfected    = "attachment_infected"
+	CodeAttachmentQuarantined = "attachment_quarantined"
+	CodeAttachmentsNotClean   = "attachments_not_clean"
+)
+
+// AttachmentError is a rejected upload or attachment list, with the
+// numbers behind the decision in Details.
+type AttachmentError struct {
+	Code    string
+	Message string
+	Details map[string]any
+}
+
+func (e *AttachmentError) Error() string {
+	return e.Message
+}
+
+// AttachmentPolicy bounds what can be uploaded. Zero limits are unlimited.
+type AttachmentPolicy struct {
+	AllowedTypes     []string // MIME types, matched against the sniffed content
+	MaxFileBytes     int64
+	MaxEventBytes    int64         // all attachments on one event
+	AuthorQuotaBytes int64         // everything one author has stored, renditions included
+	UnlinkedTTL      time.Duration // uploads never linked to an event are deleted after this
+}
+
+// DefaultAttachmentTypes covers documents, spreadsheets, slides, images and
+// calendar invites.
+var DefaultAttachmentTypes = []string{
+	"application/pdf",
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"text/plain",
+	"text/csv",
+	"text/calendar",
+	"application/msword",
+	"application/vnd.ms-excel",
+	"application/vnd.ms-powerpoint",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+}
+
+// DefaultAttachmentPolicy applies until configured otherwise.
+var DefaultAttachmentPolicy = AttachmentPolicy{
+	AllowedTypes:     DefaultAttachmentTypes,
+	MaxFileBytes:     25 << 20,
+	MaxEventBytes:    50 << 20,
+	AuthorQuotaBytes: 1 << 30,
+	UnlinkedTTL:      24 * time.Hour,
+}
+
+// sniffLen is how much of an upload is read to detect its type.
+const sniffLen = 3072
+
+// maxFilenameBytes fits common filesystem and header limits.
+const maxFilenameBytes = 200
+
+// checkType detects the type of head, the start of the content, and
+// returns it when the policy allows it. The declared type and the file
+// extension are ignored.
+func (p AttachmentPolicy) checkType(head []byte, name string) (string, error) {
+	m := mimetype.Detect(head)
+	for _, t := range p.AllowedTypes {
+		if m.Is(t) {
+			return m.String(), nil
+		}
+	}
+	return "", &AttachmentError{
+		Code:    CodeTypeNotAllowed,
+		Message: fmt.Sprintf("%s files are not allowed", m.String()),
+		Details: map[string]any{"name": name, "detected_type": m.String(), "allowed_types": p.AllowedTypes},
+	}
+}
+
+func (p AttachmentPolicy) fileTooLarge(name string, size int64) error {
+	return &AttachmentError{
+		Code:    CodeFileTooLarge,
+		Message: fmt.Sprintf("files may be at most %d bytes", p.MaxFileBytes),
+		Details: map[string]any{"name": name, "size": size, "max_bytes": p.MaxFileBytes},
+	}
+}
+
+func (p AttachmentPolicy) quotaExceeded(name string, size, used int64) error {
+	return &AttachmentError{
+		Code:    CodeQuotaExceeded,
+		Message: "upload would exceed your attachment storage quota",
+		Details: map[string]any{"name": name, "size": size, "used_bytes": used, "quota_bytes": p.AuthorQuotaBytes},
+	}
+}
+
+// SanitizeFilename reduces an uploaded name to a safe base name: no
+// directories, control or reserved characters, or leading dots, and short
+// enough for Content-Disposition. Nothing usable becomes "attachment".
+func SanitizeFilename(name string) string {
+	name = strings.ToValidUTF8(name, "")
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+
+	var b strings.Builder
+	space := false
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		case unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r):
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteRune(r)
+	}
+
+	clean := strings.TrimLeft(b.String(), ". ")
+	clean = strings.TrimRight(clean, ". ")
+
+	if len(clean) > maxFilenameBytes {
+		ext := path.Ext(clean)
+		if len(ext) > 16 {
+			ext = ""
+		}
+		stem := clean[:maxFilenameBytes-len(ext)]
+		for !utf8.ValidString(stem) {
+			stem = stem[:len(stem)-1]
+		}
+		clean = strings.TrimRight(stem, ". ") + ext
+	}
+
+	if clean == "" {
+		return "attachment"
+	}
+	return clean
+}
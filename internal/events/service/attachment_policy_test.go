@@ -0,0 +1,53 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	cases := map[string]string{
+		"agenda.pdf":                 "agenda.pdf",
+		`C:\Users\me\Q3 report.docx`: "Q3 report.docx",
+		"../../etc/passwd":           "passwd",
+		"..hidden":                   "hidden",
+		"a\x00b\r\nc.txt":            "ab c.txt",
+		"tabs\tand   spaces .pdf":    "tabs and spaces .pdf",
+		`what?*<>|".png`:             "what.png",
+		"...":                        "attachment",
+		"":                           "attachment",
+	}
+	for in, want := range cases {
+		if got := SanitizeFilename(in); got != want {
+			t.Errorf("SanitizeFilename(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	long := SanitizeFilename(strings.Repeat("é", 300) + ".pdf")
+	if len(long) > maxFilenameBytes || !strings.HasSuffix(long, ".pdf") {
+		t.Errorf("long name not shortened: %d bytes %q", len(long), long[len(long)-8:])
+	}
+}
+
+func TestAttachmentPolicyCheckType(t *testing.T) {
+	p := DefaultAttachmentPolicy
+
+	got, err := p.checkType([]byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"), "evil.exe")
+	if err != nil || got != "application/pdf" {
+		t.Fatalf("pdf: %q %v", got, err)
+	}
+
+	_, err = p.checkType([]byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff"), "report.pdf")
+	var ae *AttachmentError
+	if !errors.As(err, &ae) || ae.Code != CodeTypeNotAllowed {
+		t.Fatalf("executable named .pdf: %v", err)
+	}
+	if ae.Details["name"] != "report.pdf" {
+		t.Errorf("details: %v", ae.Details)
+	}
+
+	if _, err := p.checkType([]byte("plain words\n"), "notes.txt"); err != nil {
+		t.Errorf("text: %v", err)
+	}
+}
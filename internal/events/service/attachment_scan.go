@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"events-service/internal/events/models"
+	"events-service/internal/scan"
+)
+
+var ErrScannerNotConfigured = errors.New("malware scanner not configured")
+
+// MaxScanAttempts is how many failed scans an attachment gets before it is
+// left in ScanError.
+const MaxScanAttempts = 8
+
+// scanRetryDelay doubles from a minute after each failed attempt, up to
+// six hours.
+func scanRetryDelay(attempts int) time.Duration {
+	d := time.Minute
+	for i := 1; i < attempts && d < 6*time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, 6*time.Hour)
+}
+
+// ScanAttachment runs the scanner over a stored attachment and records the
+// verdict. On error the attachment stays pending and is retried later with
+// backoff, until MaxScanAttempts.
+func (s *EventService) ScanAttachment(ctx context.Context, a *models.Attachment) error {
+	if s.Scanner == nil {
+		return ErrScannerNotConfigured
+	}
+
+	res, err := s.scan(ctx, a)
+	if err != nil {
+		if ferr := s.recordScanFailure(a, time.Now()); ferr != nil {
+			log.Printf("attachment scan: cannot record failure for %s: %v\n", a.ID, ferr)
+		}
+		return fmt.Errorf("scanning attachment %s: %w", a.ID, err)
+	}
+
+	status := models.ScanClean
+	if res.Infected {
+		status = models.ScanInfected
+	}
+	now := time.Now()
+	if err := s.Repo.SetAttachmentScan(a.StorageKey, status, res.Signature, now); err != nil {
+		return err
+	}
+
+	a.ScanStatus, a.ScanSignature, a.ScannedAt = status, res.Signature, &now
+	return nil
+}
+
+func (s *EventService) scan(ctx context.Context, a *models.Attachment) (scan.Result, error) {
+	rc, err := s.OpenAttachment(ctx, a)
+	if err != nil {
+		return scan.Result{}, err
+	}
+	defer rc.Close()
+
+	return s.Scanner.Scan(ctx, rc)
+}
+
+func (s *EventService) recordScanFailure(a *models.Attachment, now time.Time) error {
+	attempts := a.ScanAttempts + 1
+	status, next := models.ScanPending, now.Add(scanRetryDelay(attempts))
+	if attempts >= MaxScanAttempts {
+		status = models.ScanError
+	}
+	if err := s.Repo.SetAttachmentScanFailure(a.StorageKey, status, attempts, next); err != nil {
+		return err
+	}
+
+	a.ScanStatus, a.ScanAttempts, a.NextScanAt = status, attempts, &next
+	return nil
+}
+
+// ScanPendingAttachments scans up to limit attachments whose scan is due
+// and reports how many were decided.
+func (s *EventService) ScanPendingAttachments(ctx context.Context, limit int) (int, error) {
+	pending, err := s.Repo.PendingScans(limit, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for i := range pending {
+		if err := s.ScanAttachment(ctx, &pending[i]); err != nil {
+			log.Printf("attachment scan: %v\n", err)
+			continue
+		}
+		done++
+	}
+	return done, nil
+}
+
+// checkPublishable blocks publishing while any attachment is unscanned or
+// infected.
+func checkPublishable(attachments []models.Attachment) error {
+	var blocked []map[string]any
+	for _, a := range attachments {
+		if a.ScanStatus != models.ScanClean {
+			blocked = append(blocked, map[string]any{"id": a.ID, "name": a.Name, "scan_status": a.ScanStatus})
+		}
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+	return &AttachmentError{
+		Code:    CodeAttachmentsNotClean,
+		Message: "attachments must pass the malware scan before the event is published",
+		Details: map[string]any{"attachments": blocked},
+	}
+}
+
+// CheckPublishable reports whether the event's attachments allow it to be
+// approved or broadcast.
+func (s *EventService) CheckPublishable(evt *models.Event) error {
+	return checkPublishable(evt.Attachments)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+)
+
+func TestCheckPublishable(t *testing.T) {
+	if err := checkPublishable(nil); err != nil {
+		t.Fatalf("no attachments: %v", err)
+	}
+
+	clean := models.Attachment{ID: uuid.New(), Name: "agenda.pdf", ScanStatus: models.ScanClean}
+	if err := checkPublishable([]models.Attachment{clean}); err != nil {
+		t.Fatalf("clean: %v", err)
+	}
+
+	pending := models.Attachment{ID: uuid.New(), Name: "slides.pptx", ScanStatus: models.ScanPending}
+	infected := models.Attachment{ID: uuid.New(), Name: "invoice.docx", ScanStatus: models.ScanInfected}
+	err := checkPublishable([]models.Attachment{clean, pending, infected})
+
+	var ae *AttachmentError
+	if !errors.As(err, &ae) || ae.Code != CodeAttachmentsNotClean {
+		t.Fatalf("expected attachments_not_clean, got %v", err)
+	}
+	blocked, _ := ae.Details["attachments"].([]map[string]any)
+	if len(blocked) != 2 || blocked[0]["name"] != "slides.pptx" || blocked[1]["scan_status"] != models.ScanInfected {
+		t.Errorf("details: %v", ae.Details)
+	}
+}
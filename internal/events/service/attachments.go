@@ -0,0 +1,272 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/repository"
+	"events-service/internal/imaging"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAttachmentUnavailable = repository.ErrAttachmentUnavailable
+	ErrStorageNotConfigured  = errors.New("attachment storage not configured")
+)
+
+// attachmentKey is where an upload's bytes are stored. Ids are unique
+// across tenants, so the key needs nothing else.
+func attachmentKey(id uuid.UUID) string {
+	return "attachments/" + id.String()
+}
+
+// UploadAttachment checks an upload against the policy, streams it into
+// storage, hashing it on the way, and records it as an unlinked upload by
+// uploadedBy. size is the length the client declared.
+func (s *EventService) UploadAttachment(ctx context.Context, name string, r io.Reader, size int64, uploadedBy uuid.UUID) (*models.Attachment, error) {
+	if s.Files == nil {
+		return nil, ErrStorageNotConfigured
+	}
+	policy := s.Attachments
+	name = SanitizeFilename(name)
+
+	if policy.MaxFileBytes > 0 && size > policy.MaxFileBytes {
+		return nil, policy.fileTooLarge(name, size)
+	}
+	if policy.AuthorQuotaBytes > 0 {
+		// an early answer on the declared size; createAttachment decides
+		used, err := s.Repo.AuthorAttachmentBytes(uploadedBy)
+		if err != nil {
+			return nil, err
+		}
+		if used+size > policy.AuthorQuotaBytes {
+			return nil, policy.quotaExceeded(name, size, used)
+		}
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	head = head[:n]
+	mimeType, err := policy.checkType(head, name)
+	if err != nil {
+		return nil, err
+	}
+
+	content := io.MultiReader(bytes.NewReader(head), r)
+	if policy.MaxFileBytes > 0 {
+		// the declared size may be a lie; one byte over is enough to know
+		content = io.LimitReader(content, policy.MaxFileBytes+1)
+	}
+
+	id := uuid.New()
+	key := attachmentKey(id)
+
+	hash := sha256.New()
+	counted := &countingReader{r: io.TeeReader(content, hash)}
+	if err := s.Files.Put(ctx, key, counted, size, mimeType); err != nil {
+		return nil, err
+	}
+	if policy.MaxFileBytes > 0 && counted.n > policy.MaxFileBytes {
+		_ = s.Files.Delete(ctx, key)
+		return nil, policy.fileTooLarge(name, counted.n)
+	}
+
+	a := &models.Attachment{
+		ID:         id,
+		Name:       name,
+		Size:       counted.n,
+		MimeType:   mimeType,
+		Checksum:   hex.EncodeToString(hash.Sum(nil)),
+		StorageKey: key,
+		UploadedBy: uploadedBy,
+		// renditions are made by the scan worker once the image scans clean
+		NeedsVariants: imaging.Decodable(mimeType),
+	}
+	if err := s.createAttachment(a); err != nil {
+		_ = s.Files.Delete(ctx, key)
+		return nil, err
+	}
+
+	// a first attempt now; the scan worker retries whatever stays pending
+	if s.Scanner != nil {
+		if err := s.ScanAttachment(ctx, a); err != nil {
+			log.Printf("attachment scan: %v\n", err)
+		}
+	}
+	return a, nil
+}
+
+// createAttachment records the stored upload, checking the author's quota
+// against its actual size in the same transaction.
+func (s *EventService) createAttachment(a *models.Attachment) error {
+	policy := s.Attachments
+	if policy.AuthorQuotaBytes <= 0 {
+		return s.Repo.CreateAttachment(a)
+	}
+	used, err := s.Repo.CreateAttachmentWithinQuota(a, policy.AuthorQuotaBytes)
+	if errors.Is(err, repository.ErrQuotaExceeded) {
+		return policy.quotaExceeded(a.Name, a.Size, used)
+	}
+	return err
+}
+
+// DeleteAttachment deletes actor's unlinked upload, with its stored object
+// and renditions unless a clone shares them.
+func (s *EventService) DeleteAttachment(ctx context.Context, id, actor uuid.UUID) error {
+	orphans, err := s.Repo.DeleteAttachment(id, actor)
+	if err != nil {
+		return err
+	}
+	s.removeObjects(ctx, orphans)
+	return nil
+}
+
+// SweepUnlinkedUploads deletes up to limit uploads left unlinked for longer
+// than the policy's UnlinkedTTL, with their stored objects, and reports how
+// many rows went.
+func (s *EventService) SweepUnlinkedUploads(ctx context.Context, limit int) (int, error) {
+	if s.Attachments.UnlinkedTTL <= 0 {
+		return 0, nil
+	}
+	stale, err := s.Repo.StaleUploads(time.Now().Add(-s.Attachments.UnlinkedTTL), limit)
+	if err != nil || len(stale) == 0 {
+		return 0, err
+	}
+
+	orphans, err := s.Repo.DeleteUnlinkedAttachments(stale)
+	if err != nil {
+		return 0, err
+	}
+	s.removeObjects(ctx, orphans)
+	return len(stale), nil
+}
+
+// removeObjects deletes the stored objects and renditions of attachments
+// whose rows are already gone. Failures only leak storage, so they are
+// logged.
+func (s *EventService) removeObjects(ctx context.Context, attachments []models.Attachment) {
+	if s.Files == nil {
+		return
+	}
+	for i := range attachments {
+		a := &attachments[i]
+		keys := []string{a.StorageKey}
+		for _, v := range a.Variants {
+			keys = append(keys, a.VariantKey(v.Name))
+		}
+		for _, key := range keys {
+			if err := s.Files.Delete(ctx, key); err != nil {
+				log.Printf("attachment cleanup: cannot delete %s: %v\n", key, err)
+			}
+		}
+	}
+}
+
+func (s *EventService) GetAttachment(id uuid.UUID) (*models.Attachment, error) {
+	return s.Repo.GetAttachment(id)
+}
+
+// OpenAttachment returns a reader over the attachment's content; the caller
+// closes it.
+func (s *EventService) OpenAttachment(ctx context.Context, a *models.Attachment) (io.ReadCloser, error) {
+	if s.Files == nil {
+		return nil, ErrStorageNotConfigured
+	}
+	return s.Files.Open(ctx, a.StorageKey)
+}
+
+// CreateEventWithAttachments creates the event with attachmentIDs, which
+// must be actor's own unlinked uploads.
+func (s *EventService) CreateEventWithAttachments(event models.Event, body models.AnnouncementBody, tags []models.EventTag, attachmentIDs []uuid.UUID, actor uuid.UUID) error {
+	if err := s.checkEventAttachments(attachmentIDs, event.Status == "approved"); err != nil {
+		return err
+	}
+	if event.CoverAttachmentID != nil {
+		if err := s.CheckCover(*event.CoverAttachmentID, attachmentIDs); err != nil {
+			return err
+		}
+	}
+	return s.Repo.CreateEventWithAttachments(&event, &body, tags, attachmentIDs, actor)
+}
+
+// SetEventAttachments replaces the event's attachments. Approved events
+// only take attachments that have already passed the malware scan.
+func (s *EventService) SetEventAttachments(eventID uuid.UUID, attachmentIDs []uuid.UUID, actor uuid.UUID) error {
+	evt, err := s.Repo.GetEvent(eventID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkEventAttachments(attachmentIDs, evt.Status == "approved"); err != nil {
+		return err
+	}
+	return s.Repo.SetEventAttachments(eventID, attachmentIDs, actor)
+}
+
+// checkEventAttachments refuses infected files, unscanned ones when the
+// event is already published, and lists over the per-event size limit.
+// Whether the attachments may be linked at all is checked with the link.
+func (s *EventService) checkEventAttachments(ids []uuid.UUID, published bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := s.Repo.GetAttachments(ids)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range rows {
+		if a.ScanStatus == models.ScanInfected {
+			return &AttachmentError{
+				Code:    CodeAttachmentInfected,
+				Message: fmt.Sprintf("%s failed the malware scan", a.Name),
+				Details: map[string]any{"id": a.ID, "name": a.Name, "signature": a.ScanSignature},
+			}
+		}
+	}
+	if published {
+		if err := checkPublishable(rows); err != nil {
+			return err
+		}
+	}
+
+	limit := s.Attachments.MaxEventBytes
+	if limit <= 0 {
+		return nil
+	}
+	var total int64
+	for _, a := range rows {
+		total += a.Size
+	}
+	if total > limit {
+		return &AttachmentError{
+			Code:    CodeEventAttachmentsLimit,
+			Message: fmt.Sprintf("an event's attachments may total at most %d bytes", limit),
+			Details: map[string]any{"total_bytes": total, "max_bytes": limit},
+		}
+	}
+	return nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidAudience = errors.New("invalid audience")
+
+const (
+	maxAudienceValues = 50   // per department, location or role list
+	maxAudienceUsers  = 1000 // explicit user ids
+)
+
+// NormalizeAudience cleans up an audience and checks its size.
+func NormalizeAudience(a models.Audience) (models.Audience, error) {
+	a = a.Normalize()
+	for name, n := range map[string]int{
+		"departments": len(a.Departments),
+		"locations":   len(a.Locations),
+		"roles":       len(a.Roles),
+	} {
+		if n > maxAudienceValues {
+			return a, fmt.Errorf("%w: at most %d %s", ErrInvalidAudience, maxAudienceValues, name)
+		}
+	}
+	if len(a.UserIDs) > maxAudienceUsers {
+		return a, fmt.Errorf("%w: at most %d user_ids", ErrInvalidAudience, maxAudienceUsers)
+	}
+	return a, nil
+}
+
+// Viewer describes userID for feed filtering. Staff missing from the
+// directory only see events for everyone, events naming them and their own.
+func (s *EventService) Viewer(userID uuid.UUID, seeAll bool) (*repository.Viewer, error) {
+	v := &repository.Viewer{UserID: userID, SeeAll: seeAll}
+	if seeAll {
+		return v, nil
+	}
+
+	profile, err := s.Repo.GetAppUser(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if profile != nil {
+		v.Profile = *profile
+	}
+	v.Profile.ID = userID
+	return v, nil
+}
+
+// CanView applies the feed's visibility rule to a single event.
+func CanView(evt *models.Event, v *repository.Viewer) bool {
+	if v == nil || v.SeeAll || evt.CreatedBy == v.UserID {
+		return true
+	}
+	return evt.Audience.Data().Includes(v.Profile)
+}
+
+func (s *EventService) CountAudience(a models.Audience) (int64, error) {
+	return s.Repo.CountAudience(a)
+}
+
+func (s *EventService) GetAudienceEmails(a models.Audience) ([]string, error) {
+	return s.Repo.FetchAudienceEmails(a)
+}
+
+func (s *EventService) GetAppUsers(ids []uuid.UUID) ([]models.AppUser, error) {
+	return s.Repo.ListAppUsers(ids)
+}
+
+func (s *EventService) SetEventAudience(eventID uuid.UUID, a models.Audience) error {
+	return s.Repo.SetEventAudience(eventID, a)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+func TestNormalizeAudience(t *testing.T) {
+	id := uuid.New()
+	a, err := NormalizeAudience(models.Audience{
+		Departments: []string{" Engineering", "engineering", ""},
+		Locations:   []string{"Colombo"},
+		UserIDs:     []uuid.UUID{id, id, uuid.Nil},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Departments) != 1 || a.Departments[0] != "engineering" {
+		t.Fatalf("departments: %v", a.Departments)
+	}
+	if a.Locations[0] != "colombo" || len(a.UserIDs) != 1 {
+		t.Fatalf("unexpected audience: %+v", a)
+	}
+
+	big := models.Audience{}
+	for i := 0; i <= maxAudienceValues; i++ {
+		big.Locations = append(big.Locations, uuid.NewString())
+	}
+	if _, err := NormalizeAudience(big); !errors.Is(err, ErrInvalidAudience) {
+		t.Fatalf("expected ErrInvalidAudience, got %v", err)
+	}
+}
+
+func TestCanView(t *testing.T) {
+	colomboEng := models.AppUser{ID: uuid.New(), Department: "Engineering", Location: "Colombo"}
+	londonEng := models.AppUser{ID: uuid.New(), Department: "Engineering", Location: "London"}
+	viewer := func(u models.AppUser) *repository.Viewer { return &repository.Viewer{UserID: u.ID, Profile: u} }
+
+	evt := &models.Event{CreatedBy: uuid.New(), Audience: datatypes.NewJSONType(models.Audience{
+		Locations: []string{"colombo"},
+	})}
+
+	if !CanView(evt, viewer(colomboEng)) {
+		t.Fatal("colombo staff should see a colombo notice")
+	}
+	if CanView(evt, viewer(londonEng)) {
+		t.Fatal("london staff should not see a colombo notice")
+	}
+	if !CanView(evt, &repository.Viewer{UserID: londonEng.ID, SeeAll: true}) {
+		t.Fatal("moderators see everything")
+	}
+
+	evt.Audience = datatypes.NewJSONType(models.Audience{Locations: []string{"colombo"}, UserIDs: []uuid.UUID{londonEng.ID}})
+	if !CanView(evt, viewer(londonEng)) {
+		t.Fatal("explicitly named staff should see the notice")
+	}
+
+	evt.Audience = datatypes.NewJSONType(models.Audience{})
+	if !CanView(evt, viewer(londonEng)) {
+		t.Fatal("empty audience is everyone")
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"events-service/internal/events/models"
+	"events-service/internal/events/repository"
+
+	"github.com/google/uuid"
+)
+
+// MaxBulkBroadcasts caps how many jobs one bulk action may touch.
+const MaxBulkBroadcasts = 500
+
+// BroadcastDetail is a job together with its failed attempts.
+type BroadcastDetail struct {
+	Job      *models.BroadcastQueue    `json:"job"`
+	Attempts []models.BroadcastAttempt `json:"attempts"`
+}
+
+func (s *EventService) ListBroadcasts(f repository.BroadcastFilter, page, size int) ([]models.BroadcastQueue, int64, error) {
+	return s.Repo.ListBroadcasts(f, page, size)
+}
+
+func (s *EventService) GetBroadcast(id int) (*BroadcastDetail, error) {
+	job, err := s.Repo.GetBroadcast(id)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.Repo.ListBroadcastAttempts(id)
+	if err != nil {
+		return nil, err
+	}
+	return &BroadcastDetail{Job: job, Attempts: attempts}, nil
+}
+
+// ApplyBroadcastAction retries, cancels or purges one job; see
+// repository.ApplyBroadcastAction for which states allow which action.
+func (s *EventService) ApplyBroadcastAction(id int, action string, actor uuid.UUID) error {
+	return s.Repo.ApplyBroadcastAction(id, action, actor)
+}
+
+// BroadcastIDs returns the ids of the jobs matching f, at most
+// MaxBulkBroadcasts+1 of them so callers can tell the filter was too broad.
+func (s *EventService) BroadcastIDs(f repository.BroadcastFilter) ([]int, error) {
+	jobs, _, err := s.Repo.ListBroadcasts(f, 1, MaxBulkBroadcasts+1)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(jobs))
+	for _, j := range jobs {
+		ids = append(ids, j.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"time"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/repository"
+
+	"github.com/google/uuid"
+)
+
+// CloneOverrides replaces fields on the copy; nil keeps the source title
+// and leaves the clone unscheduled.
+type CloneOverrides struct {
+	Title       *string
+	ScheduledAt *time.Time
+}
+
+// CloneEvent copies the title, summary, body (with attachments), tags and
+// audience of an existing event into a new draft owned by createdBy. Status,
+// audit history and broadcast jobs stay with the source event.
+func (s *EventService) CloneEvent(sourceID uuid.UUID, createdBy uuid.UUID, overrides CloneOverrides) (uuid.UUID, error) {
+	src, err := s.Repo.GetEvent(sourceID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	eventID := uuid.New()
+
+	event := models.Event{
+		ID:          eventID,
+		Title:       src.Title,
+		Summary:     src.Summary,
+		CreatedBy:   createdBy,
+		Status:      "draft",
+		ScheduledAt: overrides.ScheduledAt,
+		Audience:    src.Audience,
+	}
+	if overrides.Title != nil {
+		event.Title = *overrides.Title
+	}
+
+	attachments := src.Body.Attachments
+	if len(attachments) == 0 {
+		attachments = []byte("[]")
+	}
+
+	body := models.AnnouncementBody{
+		ID:          uuid.New(),
+		EventID:     eventID,
+		Body:        src.Body.Body,
+		Attachments: append([]byte(nil), attachments...),
+	}
+
+	var tags []models.EventTag
+	for _, t := range src.Tags {
+		tags = append(tags, models.EventTag{
+			EventID: eventID,
+			Tag:     t.Tag,
+		})
+	}
+
+	// a clone that lost its attachments is worse than no clone
+	err = s.Repo.Transaction(func(repo *repository.EventRepository) error {
+		if err := repo.CreateEvent(&event, &body, tags); err != nil {
+			return err
+		}
+		return repo.CopyEventAttachments(sourceID, eventID, src.CoverAttachmentID)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return eventID, nil
+}
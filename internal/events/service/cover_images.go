@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"events-service/internal/events/models"
+	"events-service/internal/imaging"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	CodeInvalidCover  = "invalid_cover"
+	CodeCoverNotReady = "cover_not_ready"
+)
+
+var ErrNoVariant = errors.New("image variant not found")
+
+// makeVariants stores the imaging.CoverSizes renditions of a clean image
+// and records them on a. Images that cannot be decoded are recorded with
+// none and simply cannot be used as covers; other errors leave them to be
+// retried.
+func (s *EventService) makeVariants(ctx context.Context, a *models.Attachment) error {
+	rc, err := s.OpenAttachment(ctx, a)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return err
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data))
+	if err != nil {
+		if serr := s.Repo.SetAttachmentVariants(a.StorageKey, nil); serr != nil {
+			return serr
+		}
+		a.NeedsVariants = false
+		return fmt.Errorf("attachment %s: %w", a.ID, err)
+	}
+
+	variants := make([]models.ImageVariant, 0, len(imaging.CoverSizes))
+	for _, size := range imaging.CoverSizes {
+		scaled := imaging.Fit(img, size.MaxWidth)
+		var buf bytes.Buffer
+		if err := imaging.EncodeJPEG(&buf, scaled); err != nil {
+			return err
+		}
+		n := int64(buf.Len())
+		if err := s.Files.Put(ctx, a.VariantKey(size.Name), &buf, n, "image/jpeg"); err != nil {
+			return err
+		}
+		b := scaled.Bounds()
+		variants = append(variants, models.ImageVariant{Name: size.Name, Width: b.Dx(), Height: b.Dy(), Size: n})
+	}
+
+	if err := s.Repo.SetAttachmentVariants(a.StorageKey, variants); err != nil {
+		return err
+	}
+	a.Variants, a.NeedsVariants = variants, false
+	return nil
+}
+
+// MakePendingVariants renders up to limit clean images still without
+// renditions and reports how many were done.
+func (s *EventService) MakePendingVariants(ctx context.Context, limit int) (int, error) {
+	pending, err := s.Repo.PendingVariants(limit)
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for i := range pending {
+		if err := s.makeVariants(ctx, &pending[i]); err != nil {
+			log.Printf("attachment variants: %v\n", err)
+			continue
+		}
+		done++
+	}
+	return done, nil
+}
+
+// OpenVariant returns a reader over one rendition of an image attachment;
+// the caller closes it.
+func (s *EventService) OpenVariant(ctx context.Context, a *models.Attachment, name string) (io.ReadCloser, models.ImageVariant, error) {
+	v, ok := a.Variant(name)
+	if !ok {
+		return nil, v, ErrNoVariant
+	}
+	if s.Files == nil {
+		return nil, v, ErrStorageNotConfigured
+	}
+	rc, err := s.Files.Open(ctx, a.VariantKey(name))
+	return rc, v, err
+}
+
+// CheckCover makes sure coverID is one of attachmentIDs and an image with
+// renditions.
+func (s *EventService) CheckCover(coverID uuid.UUID, attachmentIDs []uuid.UUID) error {
+	linked := false
+	for _, id := range attachmentIDs {
+		if id == coverID {
+			linked = true
+			break
+		}
+	}
+	if !linked {
+		return &AttachmentError{
+			Code:    CodeInvalidCover,
+			Message: "the cover image must be one of the event's attachments",
+			Details: map[string]any{"id": coverID},
+		}
+	}
+
+	a, err := s.Repo.GetAttachment(coverID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAttachmentUnavailable
+	}
+	if err != nil {
+		return err
+	}
+	if a.NeedsVariants {
+		return &AttachmentError{
+			Code:    CodeCoverNotReady,
+			Message: fmt.Sprintf("%s is still being scanned and resized; try again shortly", a.Name),
+			Details: map[string]any{"id": a.ID, "name": a.Name, "scan_status": a.ScanStatus},
+		}
+	}
+	if len(a.Variants) == 0 {
+		return &AttachmentError{
+			Code:    CodeInvalidCover,
+			Message: fmt.Sprintf("%s is not an image that can be used as a cover", a.Name),
+			Details: map[string]any{"id": a.ID, "name": a.Name, "mime_type": a.MimeType},
+		}
+	}
+	return nil
+}
+
+// SetEventCover makes one of the event's image attachments its cover, or
+// with nil removes the cover.
+func (s *EventService) SetEventCover(eventID uuid.UUID, coverID *uuid.UUID) error {
+	if coverID == nil {
+		return s.Repo.SetEventCover(eventID, nil)
+	}
+
+	evt, err := s.Repo.GetEvent(eventID)
+	if err != nil {
+		return err
+	}
+	ids := make([]uuid.UUID, 0, len(evt.Attachments))
+	for _, a := range evt.Attachments {
+		ids = append(ids, a.ID)
+	}
+	if err := s.CheckCover(*coverID, ids); err != nil {
+		return err
+	}
+	return s.Repo.SetEventCover(eventID, coverID)
+}
+
+// GetPublishedCover returns the attachment when it is the clean cover of an
+// approved event. It needs no viewer: covers of published events are
+// public so push services and mail clients can fetch them.
+func (s *EventService) GetPublishedCover(id uuid.UUID) (*models.Attachment, error) {
+	return s.Repo.GetPublishedCover(id)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+
+	"events-service/internal/render"
+
+	"github.com/google/uuid"
+)
+
+// pushBodyLimit keeps push text within what mobile notifications display.
+const pushBodyLimit = 240
+
+func (s *EventService) ArchiveEvent(eventID uuid.UUID) error {
+	return s.Repo.ArchiveEvent(eventID)
+}
+
+// DeleteEvent deletes the event and its attachments, with the stored
+// objects clones do not share.
+func (s *EventService) DeleteEvent(eventID uuid.UUID) error {
+	orphans, err := s.Repo.DeleteEvent(eventID)
+	if err != nil {
+		return err
+	}
+	// the rows are gone either way; don't let a dropped request leak objects
+	s.removeObjects(context.Background(), orphans)
+	return nil
+}
+
+func (s *EventService) AddEventTags(eventID uuid.UUID, tags []string) error {
+	return s.Repo.AddEventTags(eventID, tags)
+}
+
+func (s *EventService) RemoveEventTags(eventID uuid.UUID, tags []string) error {
+	return s.Repo.RemoveEventTags(eventID, tags)
+}
+
+// QueueApprovalBroadcasts enqueues the delivery jobs that follow an
+// approval: a push with the title, summary and a plain-text excerpt of the
+// body, plus email and Teams. Channels this deployment has no notifier for
+// are skipped.
+func (s *EventService) QueueApprovalBroadcasts(eventID uuid.UUID) error {
+	evt, err := s.Repo.GetEvent(eventID)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"title":   evt.Title,
+		"summary": evt.Summary,
+		"body":    render.Truncate(render.Text(evt.Body.Body), pushBodyLimit),
+	}
+
+	for _, job := range []struct {
+		channel string
+		payload map[string]any
+	}{
+		{"fcm", payload},
+		{"email", nil},
+		{"teams", nil},
+	} {
+		if !s.Channels.Has(job.channel) {
+			continue
+		}
+		if err := s.EnqueueBroadcast(eventID, job.channel, job.payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliverable keeps the channels a notifier is registered for, so follow-up
+// jobs are not queued only to fail.
+func (s *EventService) deliverable(channels []string) []string {
+	out := make([]string, 0, len(channels))
+	for _, ch := range channels {
+		if s.Channels.Has(ch) {
+			out = append(out, ch)
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"events-service/internal/events/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrNotRetractable = repository.ErrNotRetractable
+
+type RetractResult struct {
+	CancelledJobs  int64    `json:"cancelled_jobs"`
+	RecallChannels []string `json:"recall_channels"`
+}
+
+// RetractEvent withdraws an approved event from the feed and cancels its
+// pending broadcasts. With notify set, a recall message is queued on every
+// channel the event was already delivered on that is still configured.
+func (s *EventService) RetractEvent(eventID uuid.UUID, reason string, retractedBy uuid.UUID, notify bool) (*RetractResult, error) {
+	evt, err := s.Repo.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the repository re-checks the status as it changes it
+	cancelled, err := s.Repo.RetractEvent(eventID, reason, retractedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RetractResult{CancelledJobs: cancelled, RecallChannels: []string{}}
+	if !notify {
+		return result, nil
+	}
+
+	channels, err := s.Repo.DeliveredChannels(eventID)
+	if err != nil {
+		return result, err
+	}
+
+	payload := map[string]any{
+		"kind":    "recall",
+		"title":   "Withdrawn: " + evt.Title,
+		"summary": reason,
+		"reason":  reason,
+	}
+
+	for _, ch := range s.deliverable(channels) {
+		if err := s.EnqueueBroadcast(eventID, ch, payload); err != nil {
+			return result, err
+		}
+		result.RecallChannels = append(result.RecallChannels, ch)
+	}
+
+	if len(result.RecallChannels) > 0 {
+		_ = s.CreatePublishAudit(eventID, "retraction", "recall_queued", map[string]any{
+			"channels": result.RecallChannels,
+		})
+	}
+
+	return result, nil
+}
+
+// CancelIfRetracted cancels a claimed job of an event that was retracted
+// after the job was queued; the worker calls it before delivering anything
+// but a recall.
+func (s *EventService) CancelIfRetracted(jobID int, eventID uuid.UUID, owner string) (bool, error) {
+	return s.Repo.CancelIfRetracted(jobID, eventID, owner)
+}
@@ -3,17 +3,38 @@ package service
 import (
 	"events-service/internal/events/models"
 	"events-service/internal/events/repository"
+	"events-service/internal/notify"
+	"events-service/internal/scan"
+	"events-service/internal/storage"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrLeaseLost is returned by lease-guarded broadcast job updates once the
+// worker no longer holds the job.
+var ErrLeaseLost = repository.ErrLeaseLost
+
+// ErrNotModeratable is returned when moderating an event that is no longer
+// awaiting moderation.
+var ErrNotModeratable = repository.ErrNotModeratable
+
 type EventService struct {
-    Repo *repository.EventRepository
+    Repo        *repository.EventRepository
+    Files       storage.Store // attachment content; nil disables uploads
+    Attachments AttachmentPolicy
+    Scanner     scan.Scanner // nil leaves every upload quarantined
+    Links       LinkPolicy
+    Channels    *notify.Registry // delivery channels broadcasts can be queued on
 }
 
 func NewEventService(repo *repository.EventRepository) *EventService {
-    return &EventService{Repo: repo}
+    return &EventService{
+        Repo:        repo,
+        Attachments: DefaultAttachmentPolicy,
+        Links:       DefaultLinkPolicy,
+        Channels:    notify.NewRegistry(),
+    }
 }
 
 func (s *EventService) CreateEvent(dto models.Event, body models.AnnouncementBody, tags []models.EventTag) error {
@@ -24,15 +45,26 @@ func (s *EventService) GetEvent(id uuid.UUID) (*models.Event, error) {
     return s.Repo.GetEvent(id)
 }
 
-func (s *EventService) GetEventFeed(page int, size int, since *time.Time, channel string) ([]models.Event, int64, error) {
-	return s.Repo.GetEventFeed(page, size, since, channel)
+func (s *EventService) GetEventFeed(page int, size int, since *time.Time, channel string, viewer *repository.Viewer) ([]models.Event, int64, error) {
+	return s.Repo.GetEventFeed(page, size, since, channel, viewer)
 }
 
 func (s *EventService) UpdateEvent(event models.Event, body models.AnnouncementBody, tags []models.EventTag) error {
     return s.Repo.UpdateEvent(&event, &body, tags)
 }
 
+// ModerateEvent records a decision. Approval is refused while any
+// attachment is unscanned or infected.
 func (s *EventService) ModerateEvent(eventID uuid.UUID, status string, moderator uuid.UUID, notes string) error {
+    if status == "approved" {
+        evt, err := s.Repo.GetEvent(eventID)
+        if err != nil {
+            return err
+        }
+        if err := checkPublishable(evt.Attachments); err != nil {
+            return err
+        }
+    }
     return s.Repo.ModerateEvent(eventID, status, moderator, notes)
 }
 
@@ -48,12 +80,37 @@ func (s *EventService) EnqueueBroadcast(eventID uuid.UUID, channel string, paylo
     return s.Repo.EnqueueBroadcast(eventID, channel, payload)
 }
 
-func (s *EventService) FetchPendingBroadcasts(limit int) ([]models.BroadcastQueue, error) {
-    return s.Repo.FetchPendingBroadcasts(limit)
+func (s *EventService) FetchPendingBroadcasts(owner string, channel string, limit int, lease time.Duration) ([]models.BroadcastQueue, error) {
+    return s.Repo.FetchPendingBroadcasts(owner, channel, limit, lease)
+}
+
+func (s *EventService) UpdateBroadcastJobStatus(jobID int, owner string, status string, attempts int, lastError *string) error {
+    return s.Repo.UpdateBroadcastJobStatus(jobID, owner, status, attempts, lastError)
+}
+
+func (s *EventService) ScheduleBroadcastRetry(jobID int, owner string, attempts int, lastError string, at time.Time) error {
+    return s.Repo.ScheduleBroadcastRetry(jobID, owner, attempts, lastError, at)
+}
+
+func (s *EventService) ExtendBroadcastLease(jobID int, owner string, lease time.Duration) error {
+    return s.Repo.ExtendBroadcastLease(jobID, owner, lease)
+}
+
+func (s *EventService) FailUnroutableBroadcasts(channels []string) ([]models.BroadcastQueue, error) {
+    return s.Repo.FailUnroutableBroadcasts(channels)
+}
+
+func (s *EventService) ReapExpiredBroadcasts(owner string, limit int, lease time.Duration) ([]models.BroadcastQueue, error) {
+    return s.Repo.ReapExpiredBroadcasts(owner, limit, lease)
 }
 
-func (s *EventService) UpdateBroadcastJobStatus(jobID int, status string, attempts int, lastError *string) error {
-    return s.Repo.UpdateBroadcastJobStatus(jobID, status, attempts, lastError)
+func (s *EventService) RecordBroadcastAttempt(jobID int, attempt int, lastError string, retryable bool) error {
+    return s.Repo.RecordBroadcastAttempt(&models.BroadcastAttempt{
+        JobID:     jobID,
+        Attempt:   attempt,
+        Error:     lastError,
+        Retryable: retryable,
+    })
 }
 
 func (s *EventService) CreatePublishAudit(eventID uuid.UUID, channel, status string, details map[string]any) error {
@@ -1,13 +1,27 @@
 package service_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
+	"events-service/internal/auth"
 	"events-service/internal/events/models"
 	"events-service/internal/events/repository"
 	"events-service/internal/events/service"
+	"events-service/internal/notify"
+	"events-service/internal/scan"
+	"events-service/internal/storage"
+	"events-service/internal/tenant"
+	"events-service/internal/urlsign"
 
 	"github.com/glebarez/sqlite"
 	"github.com/google/uuid"
@@ -33,7 +47,15 @@ func setupInMemoryDB(t *testing.T) *gorm.DB {
 		&models.EventTag{},
 		&models.FeedMeta{},
 		&models.PublishAudit{},
+		&models.PublishAuditHead{},
 		&models.BroadcastQueue{},
+		&models.Attachment{},
+		&models.SigningKey{},
+		&models.BroadcastAttempt{},
+		&models.AppUser{},
+		&models.EventTemplate{},
+		&models.APIKey{},
+		&models.UserRole{},
 	)
 	if err != nil {
 		t.Fatalf("failed AutoMigrate: %v", err)
@@ -45,6 +67,25 @@ func setupInMemoryDB(t *testing.T) *gorm.DB {
 	return db
 }
 
+// newAttachmentTestService returns a service storing attachments in a
+// temporary directory, and the same service scoped to a fresh tenant.
+// configure adjusts the base service before it is scoped.
+func newAttachmentTestService(t *testing.T, configure ...func(base *service.EventService)) (*gorm.DB, *service.EventService, *service.EventService) {
+	t.Helper()
+
+	db := setupInMemoryDB(t)
+	assert.NoError(t, tenant.Register(db))
+	files, err := storage.NewLocal(t.TempDir())
+	assert.NoError(t, err)
+
+	base := service.NewEventService(repository.NewEventRepository(db))
+	base.Files = files
+	for _, fn := range configure {
+		fn(base)
+	}
+	return db, base, base.ForTenant(uuid.New())
+}
+
 // Test CreateEvent -> then GetEvent via service + repo using in-memory DB
 func TestCreateAndGetEvent(t *testing.T) {
 	db := setupInMemoryDB(t)
@@ -285,7 +326,7 @@ func TestGetEventFeed(t *testing.T) {
 	// 2. Fetch feed: page = 1, size = 3
 	// Expected newest events: 6, 5, 4
 	// -----------------------------------------
-	eventsPage1, total1, err := svc.GetEventFeed(1, 3, nil, "")
+	eventsPage1, total1, err := svc.GetEventFeed(1, 3, nil, "", nil)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(6), total1)
 	assert.Len(t, eventsPage1, 3)
@@ -302,7 +343,7 @@ func TestGetEventFeed(t *testing.T) {
 	// 3. Fetch feed: page = 2, size = 3
 	// Expected older: 3, 2, 1
 	// -----------------------------------------
-	eventsPage2, total2, err := svc.GetEventFeed(2, 3, nil, "")
+	eventsPage2, total2, err := svc.GetEventFeed(2, 3, nil, "", nil)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(6), total2)
 	assert.Len(t, eventsPage2, 3)
@@ -317,7 +358,7 @@ func TestGetEventFeed(t *testing.T) {
 	// -----------------------------------------
 	sinceTime := time.Now().Add(3 * time.Minute)
 
-	eventsSince, totalSince, err := svc.GetEventFeed(1, 10, &sinceTime, "")
+	eventsSince, totalSince, err := svc.GetEventFeed(1, 10, &sinceTime, "", nil)
 	assert.NoError(t, err)
 
 	// Should only get Event 4, 5, 6
@@ -327,4 +368,837 @@ func TestGetEventFeed(t *testing.T) {
 	assert.Equal(t, "Event 6", eventsSince[0].Title)
 	assert.Equal(t, "Event 5", eventsSince[1].Title)
 	assert.Equal(t, "Event 4", eventsSince[2].Title)
-}
\ No newline at end of file
+}
+
+func TestGetEventFeedAudience(t *testing.T) {
+	db := setupInMemoryDB(t)
+	repo := repository.NewEventRepository(db)
+	svc := service.NewEventService(repo)
+
+	me := models.AppUser{ID: uuid.New(), Email: "me@example.com", Department: "Engineering", Location: "Colombo", JobRole: "Developer"}
+	assert.NoError(t, db.Create(&me).Error)
+	someoneElse := uuid.New()
+
+	create := func(title string, createdBy uuid.UUID, a models.Audience) {
+		t.Helper()
+		evt := models.Event{ID: uuid.New(), Title: title, Status: "approved", CreatedBy: createdBy}
+		assert.NoError(t, repo.CreateEvent(&evt, &models.AnnouncementBody{ID: uuid.New(), EventID: evt.ID, Body: "body"}, nil))
+		assert.NoError(t, svc.SetEventAudience(evt.ID, a.Normalize()))
+	}
+	create("everyone", someoneElse, models.Audience{})
+	create("my department", someoneElse, models.Audience{Departments: []string{"engineering", "qa"}})
+	create("my department elsewhere", someoneElse, models.Audience{Departments: []string{"engineering"}, Locations: []string{"london"}})
+	create("my department and location", someoneElse, models.Audience{Departments: []string{"Engineering"}, Locations: []string{"colombo"}})
+	create("another role", someoneElse, models.Audience{Roles: []string{"hr"}})
+	create("names me", someoneElse, models.Audience{Roles: []string{"hr"}, UserIDs: []uuid.UUID{me.ID}})
+	create("names someone else", someoneElse, models.Audience{UserIDs: []uuid.UUID{someoneElse}})
+	create("mine", me.ID, models.Audience{Roles: []string{"hr"}})
+
+	titles := func(v *repository.Viewer) []string {
+		t.Helper()
+		events, total, err := svc.GetEventFeed(1, 20, nil, "", v)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(events)), total)
+		var out []string
+		for _, e := range events {
+			out = append(out, e.Title)
+		}
+		return out
+	}
+
+	v, err := svc.Viewer(me.ID, false)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"everyone", "my department", "my department and location", "names me", "mine"}, titles(v))
+
+	// staff missing from the directory match no dimensions
+	stranger, err := svc.Viewer(uuid.New(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"everyone"}, titles(stranger))
+
+	moderator, err := svc.Viewer(uuid.New(), true)
+	assert.NoError(t, err)
+	assert.Len(t, titles(moderator), 8)
+}
+
+func TestCloneEvent(t *testing.T) {
+	db := setupInMemoryDB(t)
+	repo := repository.NewEventRepository(db)
+	svc := service.NewEventService(repo)
+
+	sourceID := uuid.New()
+	scheduled := time.Now().Add(-90 * 24 * time.Hour).UTC()
+
+	source := models.Event{
+		ID:          sourceID,
+		Title:       "Q1 Town Hall",
+		Summary:     "Quarterly update",
+		Status:      "approved",
+		ScheduledAt: &scheduled,
+		CreatedAt:   time.Now().UTC(),
+	}
+	body := models.AnnouncementBody{
+		ID:          uuid.New(),
+		EventID:     sourceID,
+		Body:        "Agenda attached",
+		Attachments: []byte(`[{"name":"agenda.pdf"}]`),
+	}
+	tags := []models.EventTag{
+		{EventID: sourceID, Tag: "meeting"},
+	}
+	assert.NoError(t, svc.CreateEvent(source, body, tags))
+
+	owner := uuid.New()
+	title := "Q2 Town Hall"
+	cloneID, err := svc.CloneEvent(sourceID, owner, service.CloneOverrides{Title: &title})
+	assert.NoError(t, err)
+	assert.NotEqual(t, sourceID, cloneID)
+
+	got, err := svc.GetEvent(cloneID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Q2 Town Hall", got.Title)
+	assert.Equal(t, "Quarterly update", got.Summary)
+	assert.Equal(t, "draft", got.Status)
+	assert.Equal(t, owner, got.CreatedBy)
+	assert.Nil(t, got.ScheduledAt)
+	assert.Equal(t, "Agenda attached", got.Body.Body)
+	assert.JSONEq(t, `[{"name":"agenda.pdf"}]`, string(got.Body.Attachments))
+
+	if assert.Len(t, got.Tags, 1) {
+		assert.Equal(t, "meeting", got.Tags[0].Tag)
+	}
+}
+
+func TestArchiveAndRetagEvent(t *testing.T) {
+	db := setupInMemoryDB(t)
+	repo := repository.NewEventRepository(db)
+	svc := service.NewEventService(repo)
+
+	eventID := uuid.New()
+	event := models.Event{
+		ID:        eventID,
+		Title:     "Old reorg notice",
+		Status:    "approved",
+		CreatedAt: time.Now().UTC(),
+	}
+	body := models.AnnouncementBody{ID: uuid.New(), EventID: eventID, Body: "Body"}
+	assert.NoError(t, svc.CreateEvent(event, body, []models.EventTag{{EventID: eventID, Tag: "old-team"}}))
+
+	assert.NoError(t, svc.AddEventTags(eventID, []string{"new-team", "old-team"}))
+	assert.NoError(t, svc.RemoveEventTags(eventID, []string{"old-team"}))
+
+	got, err := svc.GetEvent(eventID)
+	assert.NoError(t, err)
+	if assert.Len(t, got.Tags, 1) {
+		assert.Equal(t, "new-team", got.Tags[0].Tag)
+	}
+
+	assert.NoError(t, svc.ArchiveEvent(eventID))
+
+	feed, total, err := svc.GetEventFeed(1, 10, nil, "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.Len(t, feed, 0)
+
+	assert.Error(t, svc.ArchiveEvent(uuid.New()))
+}
+
+func TestTenantIsolation(t *testing.T) {
+	db := setupInMemoryDB(t)
+	assert.NoError(t, tenant.Register(db))
+	svc := service.NewEventService(repository.NewEventRepository(db))
+
+	acmeID, globexID := uuid.New(), uuid.New()
+	acme, globex := svc.ForTenant(acmeID), svc.ForTenant(globexID)
+
+	eventID := uuid.New()
+	event := models.Event{ID: eventID, Title: "Acme only", Status: "approved", CreatedAt: time.Now().UTC()}
+	body := models.AnnouncementBody{ID: uuid.New(), EventID: eventID, Body: "Body"}
+	assert.NoError(t, acme.CreateEvent(event, body, nil))
+	assert.NoError(t, acme.EnqueueBroadcast(eventID, "email", nil))
+
+	_, err := acme.GetEvent(eventID)
+	assert.NoError(t, err)
+
+	_, err = globex.GetEvent(eventID)
+	assert.Error(t, err, "other tenant cannot read the event")
+
+	_, total, err := globex.GetEventFeed(1, 10, nil, "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+
+	assert.Error(t, globex.ArchiveEvent(eventID))
+	assert.Error(t, globex.AddEventTags(eventID, []string{"leak"}))
+	assert.Error(t, globex.ModerateEvent(eventID, "rejected", uuid.New(), ""))
+
+	var jobs []models.BroadcastQueue
+	assert.NoError(t, db.Find(&jobs).Error)
+	if assert.Len(t, jobs, 1) {
+		got, _ := acme.GetEvent(eventID)
+		assert.Equal(t, got.TenantID, jobs[0].TenantID)
+	}
+
+	tpl, err := acme.CreateTemplate(models.EventTemplate{Name: "standup", Title: "Standup", Body: "Body"})
+	assert.NoError(t, err)
+	_, err = globex.GetTemplate(tpl.ID)
+	assert.Error(t, err, "other tenant cannot read the template")
+	templates, err := globex.ListTemplates()
+	assert.NoError(t, err)
+	assert.Empty(t, templates)
+	assert.ErrorIs(t, globex.DeleteTemplate(tpl.ID), gorm.ErrRecordNotFound, "other tenant cannot delete the template")
+	assert.NoError(t, acme.DeleteTemplate(tpl.ID))
+	assert.ErrorIs(t, acme.DeleteTemplate(tpl.ID), gorm.ErrRecordNotFound)
+
+	key, _, err := acme.IssueAPIKey("ci", []string{auth.PermTagsRead}, time.Now().Add(time.Hour), uuid.New())
+	assert.NoError(t, err)
+	keys, err := globex.ListAPIKeys()
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+	assert.Error(t, globex.RevokeAPIKey(key.ID))
+	rec, err := svc.LookupAPIKey(key.Prefix)
+	if assert.NoError(t, err) {
+		assert.Equal(t, acmeID, rec.TenantID, "the key names its tenant")
+	}
+
+	user := uuid.New()
+	assert.NoError(t, db.Create(&models.UserRole{TenantID: acmeID, UserID: user, Role: "admin"}).Error)
+	roles, err := svc.UserRoles(acmeID, user)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, roles)
+	roles, err = svc.UserRoles(globexID, user)
+	assert.NoError(t, err)
+	assert.Empty(t, roles, "a grant in one tenant does not apply in another")
+}
+
+func TestEventAttachments(t *testing.T) {
+	_, _, svc := newAttachmentTestService(t)
+
+	ctx := context.Background()
+	author, other := uuid.New(), uuid.New()
+
+	agenda, err := svc.UploadAttachment(ctx, "agenda.pdf", strings.NewReader("%PDF-1.7 agenda"), 15, author)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15), agenda.Size)
+	assert.Len(t, agenda.Checksum, 64)
+	assert.Equal(t, "application/pdf", agenda.MimeType)
+	notMine, err := svc.UploadAttachment(ctx, "x.txt", strings.NewReader("x"), 1, other)
+	assert.NoError(t, err)
+
+	eventID := uuid.New()
+	event := models.Event{ID: eventID, Title: "Town hall", CreatedBy: author, Status: "draft", CreatedAt: time.Now().UTC()}
+	body := models.AnnouncementBody{ID: uuid.New(), EventID: eventID, Body: "Body", Attachments: []byte("[]")}
+
+	err = svc.CreateEventWithAttachments(event, body, nil, []uuid.UUID{agenda.ID, notMine.ID}, author)
+	assert.ErrorIs(t, err, service.ErrAttachmentUnavailable)
+	_, err = svc.GetEvent(eventID)
+	assert.Error(t, err, "event is not created when an attachment is refused")
+
+	assert.NoError(t, svc.CreateEventWithAttachments(event, body, nil, []uuid.UUID{agenda.ID}, author))
+	got, err := svc.GetEvent(eventID)
+	assert.NoError(t, err)
+	if assert.Len(t, got.Attachments, 1) {
+		assert.Equal(t, "agenda.pdf", got.Attachments[0].Name)
+	}
+
+	rc, err := svc.OpenAttachment(ctx, &got.Attachments[0])
+	assert.NoError(t, err)
+	content, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "%PDF-1.7 agenda", string(content))
+
+	cloneID, err := svc.CloneEvent(eventID, other, service.CloneOverrides{})
+	assert.NoError(t, err)
+	clone, _ := svc.GetEvent(cloneID)
+	assert.Len(t, clone.Attachments, 1)
+
+	assert.NoError(t, svc.SetEventAttachments(eventID, []uuid.UUID{}, author))
+	got, _ = svc.GetEvent(eventID)
+	assert.Len(t, got.Attachments, 0)
+}
+
+func TestCloneEventIsAtomic(t *testing.T) {
+	db, _, svc := newAttachmentTestService(t)
+	ctx := context.Background()
+	author := uuid.New()
+
+	agenda, err := svc.UploadAttachment(ctx, "agenda.pdf", strings.NewReader("%PDF-1.7 agenda"), 15, author)
+	assert.NoError(t, err)
+	eventID := uuid.New()
+	event := models.Event{ID: eventID, Title: "Town hall", CreatedBy: author, Status: "draft", CreatedAt: time.Now().UTC()}
+	body := models.AnnouncementBody{ID: uuid.New(), EventID: eventID, Body: "Body", Attachments: []byte("[]")}
+	assert.NoError(t, svc.CreateEventWithAttachments(event, body, nil, []uuid.UUID{agenda.ID}, author))
+
+	// copying the attachments fails after the clone row was written
+	assert.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:fail_attachments", func(tx *gorm.DB) {
+		if tx.Statement.Table == "attachments" {
+			tx.AddError(errors.New("disk full"))
+		}
+	}))
+
+	_, err = svc.CloneEvent(eventID, author, service.CloneOverrides{})
+	assert.Error(t, err)
+
+	var events int64
+	assert.NoError(t, db.Model(&models.Event{}).Count(&events).Error)
+	assert.Equal(t, int64(1), events, "no clone without its attachments")
+}
+
+func TestAttachmentPolicyLimits(t *testing.T) {
+	_, _, svc := newAttachmentTestService(t, func(base *service.EventService) {
+		base.Attachments.MaxFileBytes = 64
+		base.Attachments.AuthorQuotaBytes = 100
+	})
+
+	ctx := context.Background()
+	author := uuid.New()
+	var ae *service.AttachmentError
+
+	_, err := svc.UploadAttachment(ctx, "big.txt", strings.NewReader(strings.Repeat("a", 65)), 10, author)
+	if assert.ErrorAs(t, err, &ae) {
+		assert.Equal(t, service.CodeFileTooLarge, ae.Code, "declared size is not trusted")
+	}
+
+	_, err = svc.UploadAttachment(ctx, "tool.pdf", strings.NewReader("MZ\x90\x00\x03\x00"), 6, author)
+	if assert.ErrorAs(t, err, &ae) {
+		assert.Equal(t, service.CodeTypeNotAllowed, ae.Code)
+	}
+
+	_, err = svc.UploadAttachment(ctx, "one.txt", strings.NewReader(strings.Repeat("a", 60)), 60, author)
+	assert.NoError(t, err)
+	_, err = svc.UploadAttachment(ctx, "two.txt", strings.NewReader(strings.Repeat("a", 60)), 60, author)
+	if assert.ErrorAs(t, err, &ae) {
+		assert.Equal(t, service.CodeQuotaExceeded, ae.Code)
+		assert.Equal(t, int64(60), ae.Details["used_bytes"])
+	}
+
+	// understating the size passes the early check but not the insert
+	_, err = svc.UploadAttachment(ctx, "three.txt", strings.NewReader(strings.Repeat("a", 60)), 10, author)
+	if assert.ErrorAs(t, err, &ae) {
+		assert.Equal(t, service.CodeQuotaExceeded, ae.Code)
+		assert.Equal(t, int64(60), ae.Details["size"])
+	}
+}
+
+func TestAttachmentQuarantine(t *testing.T) {
+	scanner := scan.NewFake()
+	scanner.Err = fmt.Errorf("clamd: connection refused")
+	db, base, svc := newAttachmentTestService(t, func(base *service.EventService) {
+		base.Scanner = scanner
+	})
+
+	ctx := context.Background()
+	author := uuid.New()
+
+	// scanner down: the upload is kept but quarantined
+	notes, err := svc.UploadAttachment(ctx, "notes.txt", strings.NewReader("minutes of the meeting"), 22, author)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ScanPending, notes.ScanStatus)
+
+	eventID := uuid.New()
+	event := models.Event{ID: eventID, Title: "Minutes", CreatedBy: author, Status: "pending", CreatedAt: time.Now().UTC()}
+	body := models.AnnouncementBody{ID: uuid.New(), EventID: eventID, Body: "Body", Attachments: []byte("[]")}
+	assert.NoError(t, svc.CreateEventWithAttachments(event, body, nil, []uuid.UUID{notes.ID}, author))
+
+	var ae *service.AttachmentError
+	err = svc.ModerateEvent(eventID, "approved", uuid.New(), "")
+	if assert.ErrorAs(t, err, &ae) {
+		assert.Equal(t, service.CodeAttachmentsNotClean, ae.Code)
+	}
+
+	// the worker's retry clears it once the scanner is back and the
+	// backoff has passed
+	scanner.Err = nil
+	n, err := base.ScanPendingAttachments(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.NoError(t, db.Model(&models.Attachment{}).Where("id = ?", notes.ID).Update("next_scan_at", time.Now().Add(-time.Second)).Error)
+	n, err = base.ScanPendingAttachments(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.NoError(t, svc.ModerateEvent(eventID, "approved", uuid.New(), ""))
+
+	infected, err := svc.UploadAttachment(ctx, "eicar.txt", strings.NewReader(scan.EICAR), int64(len(scan.EICAR)), author)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ScanInfected, infected.ScanStatus)
+	err = svc.SetEventAttachments(eventID, []uuid.UUID{notes.ID, infected.ID}, author)
+	if assert.ErrorAs(t, err, &ae) {
+		assert.Equal(t, service.CodeAttachmentInfected, ae.Code)
+	}
+}
+
+func TestAttachmentScanGivesUp(t *testing.T) {
+	scanner := scan.NewFake()
+	scanner.Err = fmt.Errorf("clamd: connection refused")
+	db, base, svc := newAttachmentTestService(t, func(base *service.EventService) {
+		base.Scanner = scanner
+	})
+
+	ctx := context.Background()
+	a, err := svc.UploadAttachment(ctx, "notes.txt", strings.NewReader("minutes"), 7, uuid.New())
+	assert.NoError(t, err)
+
+	var stored models.Attachment
+	for i := 1; i < service.MaxScanAttempts; i++ {
+		assert.NoError(t, db.First(&stored, "id = ?", a.ID).Error)
+		assert.Equal(t, models.ScanPending, stored.ScanStatus)
+		assert.Equal(t, i, stored.ScanAttempts)
+		if assert.NotNil(t, stored.NextScanAt) && i > 1 {
+			assert.Greater(t, time.Until(*stored.NextScanAt), time.Duration(i-1)*time.Minute)
+		}
+
+		assert.NoError(t, db.Model(&stored).Update("next_scan_at", time.Now().Add(-time.Second)).Error)
+		n, err := base.ScanPendingAttachments(ctx, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	}
+
+	assert.NoError(t, db.First(&stored, "id = ?", a.ID).Error)
+	assert.Equal(t, models.ScanError, stored.ScanStatus)
+	assert.Equal(t, service.MaxScanAttempts, stored.ScanAttempts)
+
+	// no longer retried, even when due
+	scanner.Err = nil
+	assert.NoError(t, db.Model(&stored).Update("next_scan_at", time.Now().Add(-time.Second)).Error)
+	n, err := base.ScanPendingAttachments(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestEventCoverImage(t *testing.T) {
+	_, base, svc := newAttachmentTestService(t, func(base *service.EventService) {
+		base.Scanner = scan.NewFake()
+	})
+
+	ctx := context.Background()
+	author := uuid.New()
+
+	var pic bytes.Buffer
+	assert.NoError(t, png.Encode(&pic, image.NewRGBA(image.Rect(0, 0, 1000, 500))))
+	photo, err := svc.UploadAttachment(ctx, "team.png", bytes.NewReader(pic.Bytes()), int64(pic.Len()), author)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ScanClean, photo.ScanStatus)
+	assert.Empty(t, photo.Variants, "renditions are left to the scan worker")
+	agenda, err := svc.UploadAttachment(ctx, "agenda.pdf", strings.NewReader("%PDF-1.7 agenda"), 15, author)
+	assert.NoError(t, err)
+	assert.Empty(t, agenda.Variants)
+
+	eventID := uuid.New()
+	event := models.Event{ID: eventID, Title: "Offsite", CreatedBy: author, Status: "draft", CreatedAt: time.Now().UTC()}
+	body := models.AnnouncementBody{ID: uuid.New(), EventID: eventID, Body: "Body", Attachments: []byte("[]")}
+	ids := []uuid.UUID{photo.ID, agenda.ID}
+
+	var ae *service.AttachmentError
+	event.CoverAttachmentID = &agenda.ID
+	err = svc.CreateEventWithAttachments(event, body, nil, ids, author)
+	if assert.ErrorAs(t, err, &ae) {
+		assert.Equal(t, service.CodeInvalidCover, ae.Code, "a PDF cannot be a cover")
+	}
+
+	event.CoverAttachmentID = &photo.ID
+	err = svc.CreateEventWithAttachments(event, body, nil, ids, author)
+	if assert.ErrorAs(t, err, &ae) {
+		assert.Equal(t, service.CodeCoverNotReady, ae.Code)
+	}
+
+	n, err := base.MakePendingVariants(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	photo, err = svc.GetAttachment(photo.ID)
+	assert.NoError(t, err)
+	medium, ok := photo.Variant("medium")
+	assert.True(t, ok)
+	assert.Equal(t, 800, medium.Width)
+	assert.Equal(t, 400, medium.Height)
+
+	assert.NoError(t, svc.CreateEventWithAttachments(event, body, nil, ids, author))
+	got, err := svc.GetEvent(eventID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, got.Cover) {
+		rc, v, err := svc.OpenVariant(ctx, got.Cover, "thumb")
+		assert.NoError(t, err)
+		thumb, _, err := image.Decode(rc)
+		rc.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, v.Width, thumb.Bounds().Dx())
+	}
+
+	// covers of drafts are not public
+	_, err = base.GetPublishedCover(photo.ID)
+	assert.Error(t, err)
+
+	cloneID, err := svc.CloneEvent(eventID, author, service.CloneOverrides{})
+	assert.NoError(t, err)
+	clone, _ := svc.GetEvent(cloneID)
+	if assert.NotNil(t, clone.CoverAttachmentID) {
+		assert.NotEqual(t, photo.ID, *clone.CoverAttachmentID, "the clone gets its own copy")
+	}
+
+	assert.NoError(t, svc.SetEventAttachments(eventID, []uuid.UUID{agenda.ID}, author))
+	got, _ = svc.GetEvent(eventID)
+	assert.Nil(t, got.CoverAttachmentID, "dropping the cover attachment clears the cover")
+}
+
+func TestAttachmentCleanup(t *testing.T) {
+	db, base, svc := newAttachmentTestService(t, func(base *service.EventService) {
+		base.Scanner = scan.NewFake()
+		base.Attachments.UnlinkedTTL = time.Hour
+	})
+
+	ctx := context.Background()
+	author := uuid.New()
+	stored := func(key string) bool {
+		rc, err := base.Files.Open(ctx, key)
+		if err == nil {
+			rc.Close()
+		}
+		return !errors.Is(err, storage.ErrNotFound)
+	}
+	used := func() int64 {
+		n, err := svc.Repo.AuthorAttachmentBytes(author)
+		assert.NoError(t, err)
+		return n
+	}
+
+	var pic bytes.Buffer
+	assert.NoError(t, png.Encode(&pic, image.NewRGBA(image.Rect(0, 0, 400, 200))))
+	photo, err := svc.UploadAttachment(ctx, "team.png", bytes.NewReader(pic.Bytes()), int64(pic.Len()), author)
+	assert.NoError(t, err)
+	_, err = base.MakePendingVariants(ctx, 10)
+	assert.NoError(t, err)
+	photo, err = svc.GetAttachment(photo.ID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, photo.Variants)
+	assert.Greater(t, used(), photo.Size, "renditions count toward the quota")
+
+	// only the uploader can delete an unlinked upload
+	assert.ErrorIs(t, svc.DeleteAttachment(ctx, photo.ID, uuid.New()), service.ErrAttachmentUnavailable)
+	assert.NoError(t, svc.DeleteAttachment(ctx, photo.ID, author))
+	assert.False(t, stored(photo.StorageKey))
+	for _, v := range photo.Variants {
+		assert.False(t, stored(photo.VariantKey(v.Name)))
+	}
+	assert.Equal(t, int64(0), used())
+
+	// deleting an event keeps objects its clone still uses
+	notes, err := svc.UploadAttachment(ctx, "notes.txt", strings.NewReader("minutes"), 7, author)
+	assert.NoError(t, err)
+	eventID := uuid.New()
+	event := models.Event{ID: eventID, Title: "Minutes", CreatedBy: author, Status: "draft", CreatedAt: time.Now().UTC()}
+	body := models.AnnouncementBody{ID: uuid.New(), EventID: eventID, Body: "Body", Attachments: []byte("[]")}
+	assert.NoError(t, svc.CreateEventWithAttachments(event, body, nil, []uuid.UUID{notes.ID}, author))
+	assert.ErrorIs(t, svc.DeleteAttachment(ctx, notes.ID, author), service.ErrAttachmentUnavailable, "linked uploads stay")
+	cloneID, err := svc.CloneEvent(eventID, author, service.CloneOverrides{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, svc.DeleteEvent(eventID))
+	assert.True(t, stored(notes.StorageKey))
+	assert.NoError(t, svc.DeleteEvent(cloneID))
+	assert.False(t, stored(notes.StorageKey))
+	var left int64
+	db.Model(&models.Attachment{}).Count(&left)
+	assert.Equal(t, int64(0), left)
+
+	// uploads left unlinked are swept once they are old enough
+	fresh, err := svc.UploadAttachment(ctx, "fresh.txt", strings.NewReader("fresh"), 5, author)
+	assert.NoError(t, err)
+	old, err := svc.UploadAttachment(ctx, "old.txt", strings.NewReader("old"), 3, author)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Model(&models.Attachment{}).Where("id = ?", old.ID).Update("created_at", time.Now().Add(-2*time.Hour)).Error)
+
+	n, err := base.SweepUnlinkedUploads(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.False(t, stored(old.StorageKey))
+	assert.True(t, stored(fresh.StorageKey))
+	_, err = svc.GetAttachment(old.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestSignedAttachmentLinks(t *testing.T) {
+	db := setupInMemoryDB(t)
+	assert.NoError(t, tenant.Register(db))
+	base := service.NewEventService(repository.NewEventRepository(db))
+	acmeID, globexID := uuid.New(), uuid.New()
+	svc, globex := base.ForTenant(acmeID), base.ForTenant(globexID)
+
+	a := &models.Attachment{ID: uuid.New()}
+	verifyAs := func(s *service.EventService, link string) (uuid.UUID, error) {
+		u, err := url.Parse(link)
+		assert.NoError(t, err)
+		return s.VerifyLink(u.Path, u.Query())
+	}
+	verify := func(link string) error {
+		_, err := verifyAs(svc, link)
+		return err
+	}
+
+	links, err := svc.LinkSigner(time.Hour)
+	assert.NoError(t, err)
+	before := links.Attachment(a, "thumb")
+	assert.NoError(t, verify(before))
+	assert.ErrorIs(t, verify(service.AttachmentPath(a.ID)+"?variant=thumb"), urlsign.ErrUnsigned)
+
+	// links verify only against the keys of the tenant that signed them
+	signer, err := verifyAs(base, before)
+	assert.NoError(t, err)
+	assert.Equal(t, acmeID, signer, "an unscoped service learns the link's tenant")
+	_, err = verifyAs(globex, before)
+	assert.ErrorIs(t, err, urlsign.ErrUnknownKey)
+	globexLinks, err := globex.LinkSigner(time.Hour)
+	assert.NoError(t, err)
+	forged := strings.Replace(globexLinks.Attachment(a, "thumb"), "tid="+globexID.String(), "tid="+acmeID.String(), 1)
+	_, err = verifyAs(base, forged)
+	assert.ErrorIs(t, err, urlsign.ErrUnknownKey, "a key cannot vouch for another tenant")
+
+	// rotation keeps links signed with the old key working
+	_, err = svc.RotateSigningKey(uuid.New())
+	assert.NoError(t, err)
+	links, err = svc.LinkSigner(time.Hour)
+	assert.NoError(t, err)
+	after := links.Attachment(a, "thumb")
+	assert.NotEqual(t, before, after)
+	assert.NoError(t, verify(before))
+	assert.NoError(t, verify(after))
+
+	keys, err := svc.ListSigningKeys()
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 2, "the other tenant's key is not listed") {
+		assert.Nil(t, keys[0].RetiredAt)
+		assert.NotNil(t, keys[1].ExpiresAt)
+	}
+}
+
+func TestBroadcastAdminActions(t *testing.T) {
+	db := setupInMemoryDB(t)
+	assert.NoError(t, tenant.Register(db))
+	svc := service.NewEventService(repository.NewEventRepository(db)).ForTenant(uuid.New())
+	admin := uuid.New()
+
+	eventID := uuid.New()
+	assert.NoError(t, svc.EnqueueBroadcast(eventID, "email", map[string]any{"title": "Hi"}))
+	assert.NoError(t, svc.EnqueueBroadcast(eventID, "teams", map[string]any{"title": "Hi"}))
+
+	jobs, total, err := svc.ListBroadcasts(repository.BroadcastFilter{Channel: "email"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	email := jobs[0].ID
+
+	msg := "mailbox unavailable"
+	assert.NoError(t, svc.RecordBroadcastAttempt(email, 1, msg, false))
+	assert.NoError(t, svc.UpdateBroadcastJobStatus(email, "", "failed", 1, &msg))
+
+	detail, err := svc.GetBroadcast(email)
+	assert.NoError(t, err)
+	assert.Equal(t, "failed", detail.Job.Status)
+	if assert.Len(t, detail.Attempts, 1) {
+		assert.Equal(t, msg, detail.Attempts[0].Error)
+	}
+
+	// only failed and cancelled jobs can be retried
+	pending, total, _ := svc.ListBroadcasts(repository.BroadcastFilter{Status: "pending"}, 1, 10)
+	assert.Equal(t, int64(1), total)
+	teams := pending[0].ID
+	assert.ErrorIs(t, svc.ApplyBroadcastAction(teams, repository.BroadcastRetry, admin), repository.ErrBroadcastActionNotAllowed)
+
+	assert.NoError(t, svc.ApplyBroadcastAction(email, repository.BroadcastRetry, admin))
+	detail, _ = svc.GetBroadcast(email)
+	assert.Equal(t, "pending", detail.Job.Status)
+	assert.Equal(t, 0, detail.Job.Attempts)
+	assert.Len(t, detail.Attempts, 1, "history survives a retry")
+
+	assert.NoError(t, svc.ApplyBroadcastAction(email, repository.BroadcastCancel, admin))
+	assert.NoError(t, svc.ApplyBroadcastAction(email, repository.BroadcastPurge, admin))
+	_, err = svc.GetBroadcast(email)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	var audits []models.PublishAudit
+	assert.NoError(t, db.Where("status LIKE ?", "admin_%").Order("id").Find(&audits).Error)
+	if assert.Len(t, audits, 3) {
+		assert.Equal(t, "admin_retry", audits[0].Status)
+		assert.Equal(t, "admin_purge", audits[2].Status)
+	}
+}
+
+func TestBroadcastLeaseGuard(t *testing.T) {
+	db := setupInMemoryDB(t)
+	assert.NoError(t, tenant.Register(db))
+	svc := service.NewEventService(repository.NewEventRepository(db)).ForTenant(uuid.New())
+
+	assert.NoError(t, svc.EnqueueBroadcast(uuid.New(), "email", nil))
+	jobs, _, err := svc.ListBroadcasts(repository.BroadcastFilter{}, 1, 10)
+	assert.NoError(t, err)
+	id := jobs[0].ID
+
+	owner := "worker-a"
+	expires := time.Now().Add(time.Minute)
+	assert.NoError(t, db.Model(&models.BroadcastQueue{}).Where("id = ?", id).
+		Updates(map[string]any{"status": "processing", "lease_owner": owner, "lease_expires_at": expires}).Error)
+
+	// a worker whose lease was taken over cannot finish the job
+	assert.ErrorIs(t, svc.UpdateBroadcastJobStatus(id, "worker-b", "sent", 1, nil), service.ErrLeaseLost)
+	assert.ErrorIs(t, svc.ScheduleBroadcastRetry(id, "worker-b", 1, "boom", time.Now()), service.ErrLeaseLost)
+
+	assert.NoError(t, svc.UpdateBroadcastJobStatus(id, owner, "sent", 1, nil))
+	detail, err := svc.GetBroadcast(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "sent", detail.Job.Status)
+	assert.Nil(t, detail.Job.LeaseOwner)
+	assert.Nil(t, detail.Job.LeaseExpiresAt)
+
+	// the lease is gone with the job done
+	assert.ErrorIs(t, svc.UpdateBroadcastJobStatus(id, owner, "failed", 2, nil), service.ErrLeaseLost)
+}
+
+func TestApprovalBroadcastsSkipUnconfiguredChannels(t *testing.T) {
+	db := setupInMemoryDB(t)
+	assert.NoError(t, tenant.Register(db))
+	base := service.NewEventService(repository.NewEventRepository(db))
+	assert.NoError(t, base.Channels.Register(notify.NewFake("email"), notify.DefaultRetryPolicy))
+	svc := base.ForTenant(uuid.New())
+
+	eventID := uuid.New()
+	event := models.Event{ID: eventID, Title: "Approved", Status: "approved", CreatedAt: time.Now().UTC()}
+	assert.NoError(t, svc.CreateEvent(event, models.AnnouncementBody{ID: uuid.New(), EventID: eventID, Body: "Body"}, nil))
+	assert.NoError(t, svc.QueueApprovalBroadcasts(eventID))
+
+	jobs, _, err := svc.ListBroadcasts(repository.BroadcastFilter{EventID: &eventID}, 1, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, jobs, 1) {
+		assert.Equal(t, "email", jobs[0].Channel)
+	}
+}
+
+func TestRetractEvent(t *testing.T) {
+	db := setupInMemoryDB(t)
+	assert.NoError(t, tenant.Register(db))
+	base := service.NewEventService(repository.NewEventRepository(db))
+	assert.NoError(t, base.Channels.Register(notify.NewFake("email"), notify.DefaultRetryPolicy))
+	svc := base.ForTenant(uuid.New())
+
+	eventID := uuid.New()
+	event := models.Event{ID: eventID, Title: "Picnic", Status: "approved", CreatedAt: time.Now().UTC()}
+	assert.NoError(t, svc.CreateEvent(event, models.AnnouncementBody{ID: uuid.New(), EventID: eventID, Body: "Body"}, nil))
+	assert.NoError(t, svc.EnqueueBroadcast(eventID, "teams", nil))
+	assert.NoError(t, svc.CreatePublishAudit(eventID, "email", "sent", nil))
+
+	result, err := svc.RetractEvent(eventID, "cancelled by venue", uuid.New(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.CancelledJobs)
+	assert.Equal(t, []string{"email"}, result.RecallChannels)
+
+	// a second retraction finds the event no longer approved
+	_, err = svc.RetractEvent(eventID, "again", uuid.New(), true)
+	assert.ErrorIs(t, err, service.ErrNotRetractable)
+	_, err = svc.RetractEvent(uuid.New(), "missing", uuid.New(), false)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	recalls, total, err := svc.ListBroadcasts(repository.BroadcastFilter{EventID: &eventID, Status: "pending"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total, "one recall, not one per retraction")
+	if assert.Len(t, recalls, 1) {
+		assert.Equal(t, "recall", recalls[0].Payload["kind"])
+	}
+
+	// the audit rows hash the tenant they are stored under
+	report, err := base.VerifyPublishAuditChain()
+	assert.NoError(t, err)
+	assert.True(t, report.Valid, "%+v", report)
+	assert.Positive(t, report.Checked)
+
+	// dropping the newest row leaves a consistent but short chain
+	var newest models.PublishAudit
+	assert.NoError(t, db.Order("id DESC").First(&newest).Error)
+	assert.NoError(t, db.Exec("DELETE FROM publish_audit WHERE id = ?", newest.ID).Error)
+	report, err = base.VerifyPublishAuditChain()
+	assert.NoError(t, err)
+	assert.False(t, report.Valid, "truncated tail: %+v", report)
+}
+
+func TestModerateOnlyAwaitingEvents(t *testing.T) {
+	db := setupInMemoryDB(t)
+	assert.NoError(t, tenant.Register(db))
+	svc := service.NewEventService(repository.NewEventRepository(db)).ForTenant(uuid.New())
+
+	ids := map[string]uuid.UUID{}
+	for _, status := range []string{"pending", "draft", "approved", "rejected", "retracted"} {
+		ids[status] = uuid.New()
+		event := models.Event{ID: ids[status], Title: status, Status: status, CreatedAt: time.Now().UTC()}
+		assert.NoError(t, svc.CreateEvent(event, models.AnnouncementBody{ID: uuid.New(), EventID: ids[status], Body: "Body"}, nil))
+	}
+
+	assert.NoError(t, svc.ModerateEvent(ids["pending"], "approved", uuid.New(), ""))
+	assert.NoError(t, svc.ModerateEvent(ids["draft"], "rejected", uuid.New(), ""))
+
+	// approving again would re-send the announcement, and approving a
+	// retracted event would undo the retraction
+	for _, status := range []string{"approved", "rejected", "retracted"} {
+		assert.ErrorIs(t, svc.ModerateEvent(ids[status], "approved", uuid.New(), ""), service.ErrNotModeratable, status)
+	}
+	assert.ErrorIs(t, svc.ModerateEvent(ids["pending"], "approved", uuid.New(), ""), service.ErrNotModeratable)
+	assert.ErrorIs(t, svc.ModerateEvent(uuid.New(), "approved", uuid.New(), ""), gorm.ErrRecordNotFound)
+
+	got, err := svc.GetEvent(ids["retracted"])
+	assert.NoError(t, err)
+	assert.Equal(t, "retracted", got.Status)
+}
+
+func TestAuthorMayEdit(t *testing.T) {
+	db := setupInMemoryDB(t)
+	assert.NoError(t, tenant.Register(db))
+	svc := service.NewEventService(repository.NewEventRepository(db)).ForTenant(uuid.New())
+
+	eventID := uuid.New()
+	event := models.Event{ID: eventID, Title: "Picnic", Status: "approved", CreatedAt: time.Now().UTC()}
+	assert.NoError(t, svc.CreateEvent(event, models.AnnouncementBody{ID: uuid.New(), EventID: eventID, Body: "Body"}, nil))
+	assert.NoError(t, svc.EnqueueBroadcast(eventID, "email", nil))
+
+	ok, err := svc.AuthorMayEdit(&event, service.ChangeCorrection)
+	assert.NoError(t, err)
+	assert.False(t, ok, "approved but not yet delivered: the queued job would send the unreviewed edit")
+
+	assert.NoError(t, svc.CreatePublishAudit(eventID, "email", "sent", nil))
+	ok, err = svc.AuthorMayEdit(&event, service.ChangeCorrection)
+	assert.NoError(t, err)
+	assert.True(t, ok, "delivered: a correction is re-announced")
+	ok, err = svc.AuthorMayEdit(&event, service.ChangeMinor)
+	assert.NoError(t, err)
+	assert.False(t, ok, "minor edits to a delivered event need a moderator")
+
+	for status, want := range map[string]bool{"draft": true, "pending": false, "retracted": false} {
+		ok, err := svc.AuthorMayEdit(&models.Event{ID: uuid.New(), Status: status}, service.ChangeCorrection)
+		assert.NoError(t, err)
+		assert.Equal(t, want, ok, status)
+	}
+}
+
+func TestRetractedJobsAreNotDelivered(t *testing.T) {
+	db := setupInMemoryDB(t)
+	assert.NoError(t, tenant.Register(db))
+	svc := service.NewEventService(repository.NewEventRepository(db)).ForTenant(uuid.New())
+
+	live, retracted := uuid.New(), uuid.New()
+	for id, status := range map[uuid.UUID]string{live: "approved", retracted: "retracted"} {
+		event := models.Event{ID: id, Title: status, Status: status, CreatedAt: time.Now().UTC()}
+		assert.NoError(t, svc.CreateEvent(event, models.AnnouncementBody{ID: uuid.New(), EventID: id, Body: "Body"}, nil))
+		assert.NoError(t, svc.EnqueueBroadcast(id, "email", nil))
+	}
+
+	// as if a worker claimed both jobs before the retraction
+	owner := "worker-a"
+	assert.NoError(t, db.Model(&models.BroadcastQueue{}).Where("1 = 1").
+		Updates(map[string]any{"status": "processing", "lease_owner": owner}).Error)
+
+	jobs, _, err := svc.ListBroadcasts(repository.BroadcastFilter{}, 1, 10)
+	assert.NoError(t, err)
+	for _, job := range jobs {
+		cancelled, err := svc.CancelIfRetracted(job.ID, job.EventID, owner)
+		assert.NoError(t, err)
+		assert.Equal(t, job.EventID == retracted, cancelled)
+
+		detail, _ := svc.GetBroadcast(job.ID)
+		if job.EventID == retracted {
+			assert.Equal(t, "cancelled", detail.Job.Status)
+		} else {
+			assert.Equal(t, "processing", detail.Job.Status)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"events-service/internal/events/models"
+	"events-service/internal/tenant"
+	"events-service/internal/urlsign"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LinkPolicy sets how long signed attachment links stay valid.
+type LinkPolicy struct {
+	TTL        time.Duration // links in API responses, refetched as needed
+	MessageTTL time.Duration // links sent out in emails, Teams cards and push notifications
+}
+
+var DefaultLinkPolicy = LinkPolicy{
+	TTL:        time.Hour,
+	MessageTTL: 7 * 24 * time.Hour,
+}
+
+// maxTTL is how long a retired key must keep verifying.
+func (p LinkPolicy) maxTTL() time.Duration {
+	return 2 * max(p.TTL, p.MessageTTL)
+}
+
+// Expiry is when a link signed at now with ttl expires. Links are signed
+// per window of ttl, so responses stay the same within one and cached
+// copies can be revalidated; every link lives between ttl and twice that.
+func Expiry(now time.Time, ttl time.Duration) time.Time {
+	return now.Truncate(ttl).Add(2 * ttl)
+}
+
+// AttachmentPath is the download route for an attachment.
+func AttachmentPath(id uuid.UUID) string {
+	return "/api/v1/attachments/" + id.String()
+}
+
+// CoverPath is the public route for a published cover's rendition.
+func CoverPath(id uuid.UUID, variant string) string {
+	return "/media/covers/" + id.String() + "/" + variant
+}
+
+// LinkSigner signs links with one key and expiry, so a response full of
+// links needs a single key lookup.
+type LinkSigner struct {
+	key     urlsign.Key
+	expires time.Time
+}
+
+// Sign returns path and query signed.
+func (l *LinkSigner) Sign(path string, query url.Values) string {
+	return urlsign.Sign(l.key, path, query, l.expires)
+}
+
+// Attachment signs the download link for a, or one of its renditions.
+func (l *LinkSigner) Attachment(a *models.Attachment, variant string) string {
+	var q url.Values
+	if variant != "" {
+		q = url.Values{"variant": {variant}}
+	}
+	return l.Sign(AttachmentPath(a.ID), q)
+}
+
+// LinkSigner returns a signer whose links expire after ttl, signed with the
+// tenant's active key. The first key is created on first use.
+func (s *EventService) LinkSigner(ttl time.Duration) (*LinkSigner, error) {
+	tenantID, ok := tenant.IDFrom(s.Repo.DB.Statement.Context)
+	if !ok {
+		return nil, tenant.ErrNoTenant
+	}
+	key, err := s.Repo.ActiveSigningKey()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		key, err = s.newSigningKey(nil)
+		if err == nil {
+			err = s.Repo.CreateSigningKey(key)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &LinkSigner{
+		key:     urlsign.Key{Tenant: tenantID.String(), ID: key.ID, Secret: key.Secret},
+		expires: Expiry(time.Now(), ttl),
+	}, nil
+}
+
+// VerifyLink checks the signature and expiry of a request for path against
+// the keys of the tenant the link names, and returns that tenant. A service
+// scoped to a tenant only accepts its own tenant's links. It returns one of
+// the urlsign errors when the link is not acceptable.
+func (s *EventService) VerifyLink(path string, query url.Values) (uuid.UUID, error) {
+	now := time.Now()
+	scope, scoped := tenant.IDFrom(s.Repo.DB.Statement.Context)
+	var tenantID uuid.UUID
+	err := urlsign.Verify(path, query, now, func(tid, id string) ([]byte, error) {
+		var err error
+		tenantID, err = uuid.Parse(tid)
+		if err != nil || tenantID == uuid.Nil || (scoped && tenantID != scope) {
+			return nil, urlsign.ErrUnknownKey
+		}
+		key, err := s.Repo.ForTenant(tenantID).GetSigningKey(id, now)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, urlsign.ErrUnknownKey
+		}
+		if err != nil {
+			return nil, err
+		}
+		return key.Secret, nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return tenantID, nil
+}
+
+// RotateSigningKey starts signing the tenant's links with a new key. Links
+// signed with its old ones keep working until they expire.
+func (s *EventService) RotateSigningKey(actor uuid.UUID) (*models.SigningKey, error) {
+	key, err := s.newSigningKey(&actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.RotateSigningKey(key, s.Links.maxTTL()); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *EventService) ListSigningKeys() ([]models.SigningKey, error) {
+	return s.Repo.ListSigningKeys()
+}
+
+func (s *EventService) newSigningKey(createdBy *uuid.UUID) (*models.SigningKey, error) {
+	k, err := urlsign.NewKey()
+	if err != nil {
+		return nil, err
+	}
+	return &models.SigningKey{ID: k.ID, Secret: k.Secret, CreatedBy: createdBy}, nil
+}
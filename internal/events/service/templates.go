@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	templatePlaceholder  = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	templateVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	ErrInvalidTemplate = errors.New("invalid template")
+)
+
+// MissingVariablesError is returned when a template is rendered without
+// a value for every declared variable.
+type MissingVariablesError struct {
+	Missing []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return "missing template variables: " + strings.Join(e.Missing, ", ")
+}
+
+// ValidateTemplate checks that declared variables are well formed and that
+// every placeholder used in the title, summary or body is declared.
+func ValidateTemplate(tpl models.EventTemplate) error {
+	if strings.TrimSpace(tpl.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTemplate)
+	}
+
+	declared := make(map[string]bool, len(tpl.Variables))
+	for _, v := range tpl.Variables {
+		if !templateVariableName.MatchString(v.Name) {
+			return fmt.Errorf("%w: bad variable name %q", ErrInvalidTemplate, v.Name)
+		}
+		if declared[v.Name] {
+			return fmt.Errorf("%w: variable %q declared twice", ErrInvalidTemplate, v.Name)
+		}
+		declared[v.Name] = true
+	}
+
+	for _, text := range []string{tpl.Title, tpl.Summary, tpl.Body} {
+		for _, m := range templatePlaceholder.FindAllStringSubmatch(text, -1) {
+			if !declared[m[1]] {
+				return fmt.Errorf("%w: placeholder %q is not declared", ErrInvalidTemplate, m[1])
+			}
+		}
+	}
+
+	return nil
+}
+
+// RenderTemplate substitutes vars into the template's title, summary and body.
+// Every declared variable must have a non-blank value.
+func RenderTemplate(tpl models.EventTemplate, vars map[string]string) (title, summary, body string, err error) {
+	var missing []string
+	for _, v := range tpl.Variables {
+		if strings.TrimSpace(vars[v.Name]) == "" {
+			missing = append(missing, v.Name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", "", "", &MissingVariablesError{Missing: missing}
+	}
+
+	replace := func(text string) string {
+		return templatePlaceholder.ReplaceAllStringFunc(text, func(m string) string {
+			name := templatePlaceholder.FindStringSubmatch(m)[1]
+			return vars[name]
+		})
+	}
+
+	return replace(tpl.Title), replace(tpl.Summary), replace(tpl.Body), nil
+}
+
+func (s *EventService) CreateTemplate(tpl models.EventTemplate) (*models.EventTemplate, error) {
+	if err := ValidateTemplate(tpl); err != nil {
+		return nil, err
+	}
+	tpl.ID = uuid.New()
+	if err := s.Repo.CreateTemplate(&tpl); err != nil {
+		return nil, err
+	}
+	return &tpl, nil
+}
+
+func (s *EventService) GetTemplate(id uuid.UUID) (*models.EventTemplate, error) {
+	return s.Repo.GetTemplate(id)
+}
+
+func (s *EventService) ListTemplates() ([]models.EventTemplate, error) {
+	return s.Repo.ListTemplates()
+}
+
+func (s *EventService) UpdateTemplate(tpl models.EventTemplate) error {
+	if err := ValidateTemplate(tpl); err != nil {
+		return err
+	}
+	return s.Repo.UpdateTemplate(&tpl)
+}
+
+func (s *EventService) DeleteTemplate(id uuid.UUID) error {
+	return s.Repo.DeleteTemplate(id)
+}
+
+// CreateEventFromTemplate renders the template with vars and stores the
+// result as a new draft through CreateEvent. Extra tags are merged with
+// the template's default tags.
+func (s *EventService) CreateEventFromTemplate(templateID uuid.UUID, vars map[string]string, createdBy uuid.UUID, scheduledAt *time.Time, extraTags []string) (uuid.UUID, error) {
+	tpl, err := s.Repo.GetTemplate(templateID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	title, summary, bodyText, err := RenderTemplate(*tpl, vars)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	eventID := uuid.New()
+
+	event := models.Event{
+		ID:          eventID,
+		Title:       title,
+		Summary:     summary,
+		CreatedBy:   createdBy,
+		Status:      "draft",
+		ScheduledAt: scheduledAt,
+	}
+
+	body := models.AnnouncementBody{
+		ID:          uuid.New(),
+		EventID:     eventID,
+		Body:        bodyText,
+		Attachments: []byte("[]"),
+	}
+
+	var tags []models.EventTag
+	seen := make(map[string]bool)
+	for _, t := range append(append([]string{}, tpl.DefaultTags...), extraTags...) {
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		tags = append(tags, models.EventTag{
+			EventID: eventID,
+			Tag:     t,
+		})
+	}
+
+	if err := s.CreateEvent(event, body, tags); err != nil {
+		return uuid.Nil, err
+	}
+
+	return eventID, nil
+}
@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func maintenanceTemplate() models.EventTemplate {
+	return models.EventTemplate{
+		Name:    "maintenance",
+		Title:   "Maintenance: {{system}}",
+		Summary: "{{ system }} is down on {{date}}",
+		Body:    "Expect downtime for {{system}} from {{start}} to {{end}}.",
+		Variables: []models.TemplateVariable{
+			{Name: "system"},
+			{Name: "date"},
+			{Name: "start"},
+			{Name: "end"},
+		},
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tpl := maintenanceTemplate()
+	assert.NoError(t, service.ValidateTemplate(tpl))
+
+	undeclared := maintenanceTemplate()
+	undeclared.Body += " Contact {{owner}}."
+	assert.ErrorIs(t, service.ValidateTemplate(undeclared), service.ErrInvalidTemplate)
+
+	duplicate := maintenanceTemplate()
+	duplicate.Variables = append(duplicate.Variables, models.TemplateVariable{Name: "date"})
+	assert.ErrorIs(t, service.ValidateTemplate(duplicate), service.ErrInvalidTemplate)
+
+	badName := maintenanceTemplate()
+	badName.Variables = append(badName.Variables, models.TemplateVariable{Name: "not valid"})
+	assert.ErrorIs(t, service.ValidateTemplate(badName), service.ErrInvalidTemplate)
+}
+
+func TestRenderTemplate(t *testing.T) {
+	title, summary, body, err := service.RenderTemplate(maintenanceTemplate(), map[string]string{
+		"system": "VPN",
+		"date":   "Saturday",
+		"start":  "22:00",
+		"end":    "23:30",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Maintenance: VPN", title)
+	assert.Equal(t, "VPN is down on Saturday", summary)
+	assert.Equal(t, "Expect downtime for VPN from 22:00 to 23:30.", body)
+}
+
+func TestRenderTemplateMissingVariables(t *testing.T) {
+	_, _, _, err := service.RenderTemplate(maintenanceTemplate(), map[string]string{
+		"system": "VPN",
+		"start":  " ",
+	})
+
+	var missing *service.MissingVariablesError
+	if assert.True(t, errors.As(err, &missing)) {
+		assert.Equal(t, []string{"date", "end", "start"}, missing.Missing)
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+
+	"events-service/internal/events/models"
+	"events-service/internal/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ForTenant returns a service whose reads and writes are confined to one
+// tenant.
+func (s *EventService) ForTenant(tenantID uuid.UUID) *EventService {
+	return &EventService{
+		Repo:        s.Repo.ForTenant(tenantID),
+		Files:       s.Files,
+		Attachments: s.Attachments,
+		Scanner:     s.Scanner,
+		Links:       s.Links,
+		Channels:    s.Channels,
+	}
+}
+
+// TenantByID implements tenant.Store.
+func (s *EventService) TenantByID(id uuid.UUID) (*tenant.Tenant, error) {
+	return toTenant(s.Repo.GetTenant(id))
+}
+
+// TenantByHost implements tenant.Store.
+func (s *EventService) TenantByHost(host string) (*tenant.Tenant, error) {
+	return toTenant(s.Repo.GetTenantByHost(host))
+}
+
+func toTenant(t *models.Tenant, err error) (*tenant.Tenant, error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, tenant.ErrUnknownTenant
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := &tenant.Tenant{
+		ID:                 t.ID,
+		Slug:               t.Slug,
+		Name:               t.Name,
+		SESSource:          t.SESSource,
+		FCMCredentialsFile: t.FCMCredentialsFile,
+		FCMTopic:           t.FCMTopic,
+	}
+	if t.Host != nil {
+		out.Host = *t.Host
+	}
+	return out, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"events-service/internal/events/models"
+)
+
+const (
+	ChangeMinor             = "minor"
+	ChangeCorrection        = "correction"
+	ChangeSignificantUpdate = "significant_update"
+)
+
+func ValidChangeType(t string) bool {
+	return t == ChangeMinor || t == ChangeCorrection || t == ChangeSignificantUpdate
+}
+
+// AuthorMayEdit reports whether an event's author, without a moderator
+// role, may make a changeType edit to it. Drafts are theirs to edit. An
+// approved event only once some channel has delivered it, and only as a
+// correction or significant update, which re-announces the change; until
+// it goes out, its queued jobs would send content no moderator reviewed.
+func (s *EventService) AuthorMayEdit(evt *models.Event, changeType string) (bool, error) {
+	switch {
+	case evt.Status == "draft":
+		return true, nil
+	case evt.Status != "approved" || changeType == ChangeMinor:
+		return false, nil
+	}
+	channels, err := s.Repo.DeliveredChannels(evt.ID)
+	if err != nil {
+		return false, err
+	}
+	return len(channels) > 0, nil
+}
+
+// ChangedFields describes, in plain sentences, how after differs from before.
+func ChangedFields(before, after *models.Event) []string {
+	var changes []string
+
+	if before.Title != after.Title {
+		changes = append(changes, fmt.Sprintf("Title changed from %q to %q", before.Title, after.Title))
+	}
+	if before.Summary != after.Summary {
+		changes = append(changes, "Summary updated")
+	}
+	if before.Body.Body != after.Body.Body {
+		changes = append(changes, "Announcement text updated")
+	}
+	if !sameTime(before.ScheduledAt, after.ScheduledAt) {
+		if after.ScheduledAt == nil {
+			changes = append(changes, "Schedule removed")
+		} else {
+			changes = append(changes, "Rescheduled to "+after.ScheduledAt.Format(time.RFC1123))
+		}
+	}
+
+	added, removed := diffTags(before.Tags, after.Tags)
+	if len(added) > 0 {
+		changes = append(changes, "Tags added: "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, "Tags removed: "+strings.Join(removed, ", "))
+	}
+
+	return changes
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func diffTags(before, after []models.EventTag) (added, removed []string) {
+	old := make(map[string]bool, len(before))
+	for _, t := range before {
+		old[t.Tag] = true
+	}
+	cur := make(map[string]bool, len(after))
+	for _, t := range after {
+		cur[t.Tag] = true
+		if !old[t.Tag] {
+			added = append(added, t.Tag)
+		}
+	}
+	for _, t := range before {
+		if !cur[t.Tag] {
+			removed = append(removed, t.Tag)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// NotifyMaterialUpdate queues an update notice on every channel the event
+// was already delivered on that is still configured. Minor edits, and events that were never sent,
+// produce no notice. It returns the channels that were queued.
+func (s *EventService) NotifyMaterialUpdate(before, after *models.Event, changeType, note string) ([]string, error) {
+	queued := []string{}
+	if changeType == "" || changeType == ChangeMinor {
+		return queued, nil
+	}
+
+	changes := ChangedFields(before, after)
+	if len(changes) == 0 && note == "" {
+		return queued, nil
+	}
+
+	channels, err := s.Repo.DeliveredChannels(after.ID)
+	if err != nil {
+		return queued, err
+	}
+	channels = s.deliverable(channels)
+	if len(channels) == 0 {
+		return queued, nil
+	}
+
+	prefix := "Updated: "
+	if changeType == ChangeCorrection {
+		prefix = "Correction: "
+	}
+
+	summary := note
+	if summary == "" {
+		summary = strings.Join(changes, ". ")
+	}
+
+	payload := map[string]any{
+		"kind":        "update",
+		"change_type": changeType,
+		"changes":     changes,
+		"note":        note,
+		"title":       prefix + after.Title,
+		"summary":     summary,
+	}
+
+	for _, ch := range channels {
+		if err := s.EnqueueBroadcast(after.ID, ch, payload); err != nil {
+			return queued, err
+		}
+		queued = append(queued, ch)
+	}
+
+	_ = s.CreatePublishAudit(after.ID, "update_notice", "queued", map[string]any{
+		"change_type": changeType,
+		"changes":     changes,
+		"channels":    queued,
+	})
+
+	return queued, nil
+}
@@ -0,0 +1,41 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangedFields(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	later := at.Add(2 * time.Hour)
+
+	before := &models.Event{
+		Title:       "Office closed",
+		Summary:     "Closed Friday",
+		ScheduledAt: &at,
+		Body:        models.AnnouncementBody{Body: "The office is closed on Friday."},
+		Tags:        []models.EventTag{{Tag: "holiday"}, {Tag: "colombo"}},
+	}
+	after := &models.Event{
+		Title:       "Office closed Monday",
+		Summary:     "Closed Friday",
+		ScheduledAt: &later,
+		Body:        models.AnnouncementBody{Body: "The office is closed on Monday."},
+		Tags:        []models.EventTag{{Tag: "holiday"}, {Tag: "all-offices"}},
+	}
+
+	assert.Equal(t, []string{
+		`Title changed from "Office closed" to "Office closed Monday"`,
+		"Announcement text updated",
+		"Rescheduled to " + later.Format(time.RFC1123),
+		"Tags added: all-offices",
+		"Tags removed: colombo",
+	}, service.ChangedFields(before, after))
+
+	assert.Empty(t, service.ChangedFields(before, before))
+}
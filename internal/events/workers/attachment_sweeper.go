@@ -0,0 +1,59 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"events-service/internal/events/service"
+)
+
+// AttachmentSweeper deletes uploads that were never linked to an event, or
+// were dropped from one, once they are older than the attachment policy's
+// UnlinkedTTL. It runs unscoped, so it covers every tenant.
+type AttachmentSweeper struct {
+	Service      *service.EventService
+	PollInterval time.Duration
+	BatchSize    int
+	StopCh       chan struct{}
+}
+
+func NewAttachmentSweeper(svc *service.EventService) *AttachmentSweeper {
+	return &AttachmentSweeper{
+		Service:      svc,
+		PollInterval: 10 * time.Minute,
+		BatchSize:    100,
+		StopCh:       make(chan struct{}),
+	}
+}
+
+func (w *AttachmentSweeper) Start() {
+	go func() {
+		log.Println("AttachmentSweeper: started")
+		for {
+			select {
+			case <-w.StopCh:
+				log.Println("AttachmentSweeper: stopping")
+				return
+			default:
+				w.runOnce()
+				time.Sleep(w.PollInterval)
+			}
+		}
+	}()
+}
+
+func (w *AttachmentSweeper) Stop() {
+	close(w.StopCh)
+}
+
+func (w *AttachmentSweeper) runOnce() {
+	n, err := w.Service.SweepUnlinkedUploads(context.Background(), w.BatchSize)
+	if err != nil {
+		log.Printf("AttachmentSweeper: %v\n", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("AttachmentSweeper: deleted %d unlinked uploads\n", n)
+	}
+}
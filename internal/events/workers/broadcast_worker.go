@@ -2,87 +2,175 @@ package workers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"sync"
 	"time"
 
 	"events-service/internal/events/models"
 	"events-service/internal/events/service"
+	"events-service/internal/notify"
 
-	"firebase.google.com/go/v4/messaging"
 	"github.com/google/uuid"
-	"gorm.io/datatypes"
+)
+
+// BroadcastWorker delivers queued broadcast jobs through the notifier
+// registered for each job's channel.
+//
+// Claimed jobs are leased to the worker's ID for LeaseDuration and the
+// lease is extended while the job runs. Jobs whose lease runs out, because
+// their worker crashed or was redeployed mid-send, are reaped by any
+// worker and go back to pending with the attempt counted.
+type BroadcastWorker struct {
+	Service       *service.EventService
+	Channels      *notify.Registry
+	ID            string // lease owner, unique per process
+	LeaseDuration time.Duration
+	PollInterval  time.Duration
+	BatchSize     int            // most jobs claimed per channel at once
+	Concurrency   map[string]int // jobs run in parallel per channel; missing channels run one at a time
+	StopCh        chan struct{}
+
+	wg sync.WaitGroup
+}
+
+const (
+	defaultLeaseDuration = 2 * time.Minute
+	defaultPollInterval  = 5 * time.Second
+	defaultBatchSize     = 10
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/ses"
-	"github.com/aws/aws-sdk-go-v2/service/ses/types"
+	// minLeaseDuration leaves the heartbeat, at a third of the lease, a
+	// sensible interval.
+	minLeaseDuration = 3 * time.Second
 )
 
-var sesClient *ses.Client
+func NewBroadcastWorker(svc *service.EventService, channels *notify.Registry) *BroadcastWorker {
+	return &BroadcastWorker{
+		Service:       svc,
+		Channels:      channels,
+		ID:            workerID(),
+		LeaseDuration: defaultLeaseDuration,
+		PollInterval:  defaultPollInterval,
+		BatchSize:     defaultBatchSize,
+		StopCh:        make(chan struct{}),
+	}
+}
 
-func InitSES() (*ses.Client, error) {
-	if sesClient != nil {
-		return sesClient, nil
+// clampSettings replaces settings that would make the loops spin or the
+// heartbeat panic.
+func (w *BroadcastWorker) clampSettings() {
+	if w.LeaseDuration <= 0 {
+		w.LeaseDuration = defaultLeaseDuration
+	}
+	w.LeaseDuration = max(w.LeaseDuration, minLeaseDuration)
+	if w.PollInterval <= 0 {
+		w.PollInterval = defaultPollInterval
+	}
+	if w.BatchSize <= 0 {
+		w.BatchSize = defaultBatchSize
 	}
+}
 
-	cfg, err := config.LoadDefaultConfig(context.Background())
+// workerID names this process in lease_owner; the random suffix keeps
+// restarts with a recycled pid apart.
+func workerID() string {
+	host, err := os.Hostname()
 	if err != nil {
-		return nil, fmt.Errorf("SES load config error: %w", err)
+		host = "unknown"
 	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+}
 
-	sesClient = ses.NewFromConfig(cfg)
-	return sesClient, nil
+// Start runs a claim loop per registered channel, so a slow channel only
+// holds up its own jobs, and a housekeeping loop that reaps expired leases.
+func (w *BroadcastWorker) Start() {
+	w.clampSettings()
+	log.Println("BroadcastWorker: started")
+	for _, channel := range w.Channels.Names() {
+		w.wg.Add(1)
+		go w.claimLoop(channel)
+	}
+	w.wg.Add(1)
+	go w.housekeeping()
 }
 
-type BroadcastWorker struct {
-	Service      *service.EventService
-	PollInterval time.Duration
-	BatchSize    int
-	StopCh       chan struct{}
+// Stop ends the loops and waits for the jobs in flight.
+func (w *BroadcastWorker) Stop() {
+	close(w.StopCh)
+	w.wg.Wait()
+	log.Println("BroadcastWorker: stopped")
 }
 
-func NewBroadcastWorker(svc *service.EventService) *BroadcastWorker {
-	return &BroadcastWorker{
-		Service:      svc,
-		PollInterval: 5 * time.Second,
-		BatchSize:    10,
-		StopCh:       make(chan struct{}),
+// concurrency is how many jobs of channel run at once.
+func (w *BroadcastWorker) concurrency(channel string) int {
+	if n := w.Concurrency[channel]; n > 0 {
+		return n
 	}
+	return 1
 }
 
-func (w *BroadcastWorker) Start() {
-	go func() {
-		log.Println("BroadcastWorker: started")
-		for {
-			select {
-			case <-w.StopCh:
-				log.Println("BroadcastWorker: stopping")
-				return
-			default:
-				w.runOnce()
-				time.Sleep(w.PollInterval)
+// claimLoop claims jobs of one channel only while a pool slot is free to
+// run them, so no claimed job sits idle while its lease runs down.
+func (w *BroadcastWorker) claimLoop(channel string) {
+	defer w.wg.Done()
+	slots := make(chan struct{}, w.concurrency(channel))
+
+	for {
+		select {
+		case <-w.StopCh:
+			return
+		case slots <- struct{}{}:
+		}
+
+		// only this loop takes slots, so the free ones stay free
+		limit := min(w.BatchSize, cap(slots)-len(slots)+1)
+		jobs, err := w.Service.FetchPendingBroadcasts(w.ID, channel, limit, w.LeaseDuration)
+		if err != nil {
+			log.Printf("BroadcastWorker: %s fetch error: %v\n", channel, err)
+		}
+		if len(jobs) == 0 {
+			<-slots
+		}
+
+		for i, job := range jobs {
+			if i > 0 {
+				slots <- struct{}{}
 			}
+			w.wg.Add(1)
+			go func(job models.BroadcastQueue) {
+				defer w.wg.Done()
+				defer func() { <-slots }()
+				w.processJob(job)
+			}(job)
 		}
-	}()
-}
 
-func (w *BroadcastWorker) Stop() {
-	close(w.StopCh)
+		// a full batch means more may be waiting
+		if len(jobs) < limit && !w.sleep() {
+			return
+		}
+	}
 }
 
-func (w *BroadcastWorker) runOnce() {
-	jobs, err := w.Service.FetchPendingBroadcasts(w.BatchSize)
-	if err != nil {
-		log.Printf("BroadcastWorker: fetch error: %v\n", err)
-		return
-	}
-	if len(jobs) == 0 {
-		return
+func (w *BroadcastWorker) housekeeping() {
+	defer w.wg.Done()
+	for {
+		w.reap()
+		w.failUnroutable()
+		if !w.sleep() {
+			return
+		}
 	}
+}
 
-	for _, job := range jobs {
-		w.processJob(job)
+// sleep waits one poll interval; false means the worker is stopping.
+func (w *BroadcastWorker) sleep() bool {
+	select {
+	case <-w.StopCh:
+		return false
+	case <-time.After(w.PollInterval):
+		return true
 	}
 }
 
@@ -91,157 +179,154 @@ func (w *BroadcastWorker) processJob(job models.BroadcastQueue) {
 		job.ID, job.EventID.String(), job.Channel, job.Attempts)
 
 	attempts := job.Attempts + 1
-	var lastErr *string
 
-	var sendErr error
+	payloadMap := map[string]any(job.Payload)
+	kind := jobKind(payloadMap)
 
-	switch job.Channel {
+	// everything the job reads or writes stays inside its tenant
+	svc := w.Service.ForTenant(job.TenantID)
 
-	case "fcm":
-		payloadMap := map[string]any(job.Payload)
-		sendErr = sendFCM(job.EventID, payloadMap)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	go w.heartbeat(ctx, cancel, svc, job.ID)
 
-	case "email":
-		sendErr = w.sendEmailAllStaff(job.EventID)
+	// a retraction cancels pending jobs, but one may already have been claimed
+	if kind != "recall" {
+		cancelled, err := svc.CancelIfRetracted(job.ID, job.EventID, w.ID)
+		if cancelled || errors.Is(err, service.ErrLeaseLost) {
+			return
+		}
+	}
 
-	case "teams":
-		sendErr = sendTeams(job.EventID, nil)
+	notifier, err := w.Channels.Get(job.Channel)
+	if err != nil {
+		msg := err.Error()
+		_ = svc.RecordBroadcastAttempt(job.ID, attempts, msg, false)
+		_ = svc.UpdateBroadcastJobStatus(job.ID, w.ID, "failed", attempts, &msg)
+		_ = logPublishAudit(svc, job.EventID, job.Channel, "failed", map[string]any{"error": msg, "kind": kind})
+		return
+	}
 
-	default:
-		msg := "unknown channel"
-		lastErr = &msg
-		sendErr = nil
+	sendErr := notifier.Send(ctx, notify.Delivery{
+		JobID:    job.ID,
+		TenantID: job.TenantID,
+		EventID:  job.EventID,
+		Kind:     kind,
+		Payload:  payloadMap,
+		Attempt:  attempts,
+	})
+
+	// the job was reaped while sending; whoever holds it now decides
+	if errors.Is(context.Cause(ctx), service.ErrLeaseLost) {
+		log.Printf("BroadcastWorker: job id=%d lost its lease while sending, leaving it to the reaper\n", job.ID)
+		return
 	}
 
 	if sendErr != nil {
 		msg := sendErr.Error()
-		lastErr = &msg
 
-		if attempts >= 3 {
-			_ = w.Service.UpdateBroadcastJobStatus(job.ID, "failed", attempts, lastErr)
-			_ = w.logPublishAudit(job.EventID, job.Channel, "failed", map[string]any{"error": msg})
+		// permanent errors fail at once; others back off until the policy gives up
+		policy := w.Channels.Policy(job.Channel)
+		retryable := notifier.Retryable(sendErr)
+		_ = svc.RecordBroadcastAttempt(job.ID, attempts, msg, retryable)
+		if !retryable || policy.Exhausted(attempts) {
+			_ = svc.UpdateBroadcastJobStatus(job.ID, w.ID, "failed", attempts, &msg)
+			_ = logPublishAudit(svc, job.EventID, job.Channel, "failed", map[string]any{"error": msg, "kind": kind, "retryable": retryable, "attempts": attempts})
 		} else {
-			_ = w.Service.UpdateBroadcastJobStatus(job.ID, "pending", attempts, lastErr)
+			next := time.Now().Add(policy.Delay(attempts))
+			_ = svc.ScheduleBroadcastRetry(job.ID, w.ID, attempts, msg, next)
+			log.Printf("BroadcastWorker: job id=%d failed (attempt %d), retrying at %s: %v\n", job.ID, attempts, next.Format(time.RFC3339), sendErr)
 		}
 
 		return
 	}
 
-	_ = w.Service.UpdateBroadcastJobStatus(job.ID, "sent", attempts, nil)
-	_ = w.logPublishAudit(job.EventID, job.Channel, "sent", map[string]any{"note": "delivered"})
+	_ = svc.UpdateBroadcastJobStatus(job.ID, w.ID, "sent", attempts, nil)
+	_ = logPublishAudit(svc, job.EventID, job.Channel, "sent", map[string]any{"note": "delivered", "kind": kind})
 }
 
-// ============================================================
-//               SES EMAIL SENDER (Broadcast to All Staff)
-// ============================================================
+// heartbeat extends the job's lease until ctx ends. If the lease is lost
+// it cancels ctx with ErrLeaseLost to abort the send.
+func (w *BroadcastWorker) heartbeat(ctx context.Context, cancel context.CancelCauseFunc, svc *service.EventService, jobID int) {
+	t := time.NewTicker(w.LeaseDuration / 3)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			err := svc.ExtendBroadcastLease(jobID, w.ID, w.LeaseDuration)
+			if errors.Is(err, service.ErrLeaseLost) {
+				cancel(err)
+				return
+			}
+			if err != nil {
+				// the next tick tries again before the lease runs out
+				log.Printf("BroadcastWorker: job id=%d lease extension failed: %v\n", jobID, err)
+			}
+		}
+	}
+}
 
-func (w *BroadcastWorker) sendEmailAllStaff(eventID uuid.UUID) error {
-	client, err := InitSES()
+// reap takes over jobs whose lease expired. Each counts as a failed,
+// retryable attempt: it goes back to pending after the channel's backoff,
+// or fails once the retry policy is exhausted.
+func (w *BroadcastWorker) reap() {
+	jobs, err := w.Service.ReapExpiredBroadcasts(w.ID, w.BatchSize, w.LeaseDuration)
 	if err != nil {
-		return err
+		log.Printf("BroadcastWorker: reap error: %v\n", err)
+		return
 	}
 
-	// Load event content
-	event, err := w.Service.GetEvent(eventID)
-	if err != nil {
-		return fmt.Errorf("cannot load event for SES: %w", err)
+	for _, job := range jobs {
+		svc := w.Service.ForTenant(job.TenantID)
+		attempts := job.Attempts + 1
+		msg := "lease expired before the job finished"
+		_ = svc.RecordBroadcastAttempt(job.ID, attempts, msg, true)
+
+		policy := w.Channels.Policy(job.Channel)
+		if policy.Exhausted(attempts) {
+			_ = svc.UpdateBroadcastJobStatus(job.ID, w.ID, "failed", attempts, &msg)
+			_ = logPublishAudit(svc, job.EventID, job.Channel, "failed", map[string]any{"error": msg, "kind": jobKind(job.Payload), "retryable": true, "attempts": attempts})
+			continue
+		}
+
+		next := time.Now().Add(policy.Delay(attempts))
+		_ = svc.ScheduleBroadcastRetry(job.ID, w.ID, attempts, msg, next)
+		log.Printf("BroadcastWorker: reaped job id=%d (attempt %d), retrying at %s\n", job.ID, attempts, next.Format(time.RFC3339))
 	}
+}
 
-	// Load all staff emails (implement in EventService)
-	recipients, err := w.Service.GetAllStaffEmails()
+// failUnroutable fails pending jobs on channels no notifier is registered
+// for, which no claim loop would ever pick up.
+func (w *BroadcastWorker) failUnroutable() {
+	jobs, err := w.Service.FailUnroutableBroadcasts(w.Channels.Names())
 	if err != nil {
-		return fmt.Errorf("cannot load staff emails: %w", err)
-	}
-
-	if len(recipients) == 0 {
-		log.Println("SES: No recipients found")
-		return nil
-	}
-
-	// Build email
-	subject := fmt.Sprintf("[Staff Announcement] %s", event.Title)
-
-	bodyHTML := fmt.Sprintf(`
-		<h2>%s</h2>
-		<p><strong>%s</strong></p>
-		<p>%s</p>
-
-		<p>Scheduled at: %v</p>
-
-		<br/><br/>
-		<p>Regards,<br/>Eyepax Staff Management System</p>
-	`,
-		event.Title,
-		event.Summary,
-		event.Body.Body,
-		event.ScheduledAt,
-	)
-
-	for _, email := range recipients {
-		input := &ses.SendEmailInput{
-    Destination: &types.Destination{
-        ToAddresses: []string{"yasela2014@gmail.com"},
-    },
-    Message: &types.Message{
-        Body: &types.Body{
-            Html: &types.Content{
-                Charset: aws.String("UTF-8"),
-                Data:    aws.String(bodyHTML),
-            },
-        },
-        Subject: &types.Content{
-            Charset: aws.String("UTF-8"),
-            Data:    aws.String(subject),
-        },
-    },
-    Source: aws.String("yasela.d@eyepax.com"),
-}
-
-
-		_, err := client.SendEmail(context.Background(), input)
-		if err != nil {
-			log.Printf("SES: error sending to %s: %v\n", email, err)
-		} else {
-			log.Printf("SES: email sent to %s\n", email)
-		}
+		log.Printf("BroadcastWorker: unroutable sweep error: %v\n", err)
+		return
 	}
 
-	return nil
+	for _, job := range jobs {
+		svc := w.Service.ForTenant(job.TenantID)
+		msg := *job.LastError
+		_ = svc.RecordBroadcastAttempt(job.ID, job.Attempts, msg, false)
+		_ = logPublishAudit(svc, job.EventID, job.Channel, "failed", map[string]any{"error": msg, "kind": jobKind(job.Payload)})
+	}
 }
 
-// ============================================================
-//                       Audit Logger
-// ============================================================
-
-func (w *BroadcastWorker) logPublishAudit(eventID uuid.UUID, channel, status string, details map[string]any) error {
-	return w.Service.CreatePublishAudit(eventID, channel, status, details)
+// jobKind tells announcement jobs apart from follow-ups such as recalls.
+func jobKind(payload map[string]any) string {
+	if kind, ok := payload["kind"].(string); ok && kind != "" {
+		return kind
+	}
+	return "announcement"
 }
 
 // ============================================================
-//               FCM + TEAMS STUBS (existing)
+//                       Audit Logger
 // ============================================================
 
-func sendFCM(eventID uuid.UUID, payload map[string]any) error {
-	if fcmClient == nil {
-		_, err := InitFCM("fcm.json")
-		if err != nil {
-			return err
-		}
-	}
-
-	msg := &messaging.Message{
-		Topic: "events",
-		Notification: &messaging.Notification{
-			Title: payload["title"].(string),
-			Body:  payload["summary"].(string),
-		},
-	}
-
-	_, err := fcmClient.Send(context.Background(), msg)
-	return err
-}
-
-func sendTeams(eventID any, payload datatypes.JSONMap) error {
-	log.Printf("sendTeams: event=%v payload=%v (stub)\n", eventID, payload)
-	return nil
+func logPublishAudit(svc *service.EventService, eventID uuid.UUID, channel, status string, details map[string]any) error {
+	return svc.CreatePublishAudit(eventID, channel, status, details)
 }
@@ -0,0 +1,31 @@
+package workers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBroadcastWorkerClampsSettings(t *testing.T) {
+	w := &BroadcastWorker{LeaseDuration: 0, PollInterval: -time.Second, BatchSize: 0}
+	w.clampSettings()
+	if w.LeaseDuration != defaultLeaseDuration || w.PollInterval != defaultPollInterval || w.BatchSize != defaultBatchSize {
+		t.Errorf("non-positive settings kept: %+v", w)
+	}
+
+	w = &BroadcastWorker{LeaseDuration: time.Millisecond, PollInterval: time.Second, BatchSize: 3}
+	w.clampSettings()
+	if w.LeaseDuration != minLeaseDuration {
+		t.Errorf("lease = %s, want %s", w.LeaseDuration, minLeaseDuration)
+	}
+	if w.PollInterval != time.Second || w.BatchSize != 3 {
+		t.Errorf("valid settings changed: %+v", w)
+	}
+
+	if n := w.concurrency("email"); n != 1 {
+		t.Errorf("unset concurrency = %d, want 1", n)
+	}
+	w.Concurrency = map[string]int{"email": -2, "fcm": 4}
+	if w.concurrency("email") != 1 || w.concurrency("fcm") != 4 {
+		t.Errorf("concurrency not clamped: %v", w.Concurrency)
+	}
+}
@@ -0,0 +1,153 @@
+package workers
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/service"
+)
+
+// announcementEmail wraps an already sanitized body; title and summary are
+// plain text and escaped here. A non-empty coverCID shows the inline image
+// with that Content-ID above the title; attachments are listed below the
+// body.
+func announcementEmail(title, summary, body string, scheduledAt *time.Time, coverCID string, attachments []attachmentLink) (subject, bodyHTML string) {
+	subject = fmt.Sprintf("[Staff Announcement] %s", title)
+
+	cover := ""
+	if coverCID != "" {
+		cover = fmt.Sprintf(`<img src="cid:%s" alt="" style="max-width:100%%"/>`, html.EscapeString(coverCID))
+	}
+
+	files := ""
+	if len(attachments) > 0 {
+		var list strings.Builder
+		for _, a := range attachments {
+			fmt.Fprintf(&list, `<li><a href="%s">%s</a></li>`, html.EscapeString(a.URL), html.EscapeString(a.Name))
+		}
+		files = "<p>Attachments (links expire):</p><ul>" + list.String() + "</ul>"
+	}
+
+	bodyHTML = fmt.Sprintf(`
+		%s
+		<h2>%s</h2>
+		<p><strong>%s</strong></p>
+		<div>%s</div>
+		%s
+
+		<p>Scheduled at: %v</p>
+
+		<br/><br/>
+		<p>Regards,<br/>Eyepax Staff Management System</p>
+	`,
+		cover,
+		html.EscapeString(title),
+		html.EscapeString(summary),
+		body,
+		files,
+		scheduledAt,
+	)
+
+	return subject, bodyHTML
+}
+
+func recallEmail(title, reason string) (subject, bodyHTML string) {
+	subject = fmt.Sprintf("[Withdrawn] %s", title)
+
+	bodyHTML = fmt.Sprintf(`
+		<h2>This announcement has been withdrawn</h2>
+		<p>The announcement <strong>%s</strong> sent earlier is no longer valid. Please disregard it.</p>
+		<p>Reason: %s</p>
+
+		<br/><br/>
+		<p>Regards,<br/>Eyepax Staff Management System</p>
+	`,
+		html.EscapeString(title),
+		html.EscapeString(reason),
+	)
+
+	return subject, bodyHTML
+}
+
+// updateEmail lists what changed above the sanitized current body.
+func updateEmail(title, changeType string, changes []string, note, body string) (subject, bodyHTML string) {
+	label := "Updated"
+	if changeType == "correction" {
+		label = "Correction"
+	}
+	subject = fmt.Sprintf("[%s] %s", label, title)
+
+	var list strings.Builder
+	for _, c := range changes {
+		list.WriteString("<li>" + html.EscapeString(c) + "</li>")
+	}
+
+	notePara := ""
+	if note != "" {
+		notePara = "<p>" + html.EscapeString(note) + "</p>"
+	}
+
+	bodyHTML = fmt.Sprintf(`
+		<h2>%s: %s</h2>
+		<p>An announcement you received has been changed.</p>
+		%s
+		<ul>%s</ul>
+
+		<hr/>
+		<div>%s</div>
+
+		<br/><br/>
+		<p>Regards,<br/>Eyepax Staff Management System</p>
+	`,
+		label,
+		html.EscapeString(title),
+		notePara,
+		list.String(),
+		body,
+	)
+
+	return subject, bodyHTML
+}
+
+// payloadStrings reads a string list back out of a JSON payload.
+func payloadStrings(v any) []string {
+	items, _ := v.([]any)
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// attachmentLink is an attachment as listed in a message.
+type attachmentLink struct {
+	Name string
+	URL  string
+}
+
+// attachmentLinks signs download links for the event's clean attachments.
+// The links expire and still require a login that can see the event.
+func attachmentLinks(svc *service.EventService, event *models.Event, publicBaseURL string) ([]attachmentLink, error) {
+	if publicBaseURL == "" || len(event.Attachments) == 0 {
+		return nil, nil
+	}
+	signer, err := svc.LinkSigner(svc.Links.MessageTTL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign attachment links: %w", err)
+	}
+
+	var links []attachmentLink
+	for i := range event.Attachments {
+		a := &event.Attachments[i]
+		if a.ScanStatus != models.ScanClean {
+			continue
+		}
+		links = append(links, attachmentLink{Name: a.Name, URL: publicBaseURL + signer.Attachment(a, "")})
+	}
+	return links, nil
+}
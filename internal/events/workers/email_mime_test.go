@@ -0,0 +1,67 @@
+package workers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestRawEmailInlinesImages(t *testing.T) {
+	img := bytes.Repeat([]byte{0xff, 0xd8, 0x00}, 100)
+	raw, err := rawEmail("news@example.com", []string{"a@example.com"}, "Café opening", `<img src="cid:cover"/><p>hi</p>`,
+		[]inlineImage{{ContentID: "cover", ContentType: "image/jpeg", Data: img}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subj, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); subj != "Café opening" {
+		t.Errorf("subject: %q", subj)
+	}
+	mt, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mt != "multipart/related" {
+		t.Fatalf("content type: %q %v", mt, err)
+	}
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	html, err := mr.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := decodePart(t, html)
+	if !strings.Contains(string(body), "cid:cover") {
+		t.Errorf("html part: %q", body)
+	}
+
+	pic, err := mr.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pic.Header.Get("Content-ID") != "<cover>" || pic.Header.Get("Content-Type") != "image/jpeg" {
+		t.Errorf("image headers: %v", pic.Header)
+	}
+	if got := decodePart(t, pic); !bytes.Equal(got, img) {
+		t.Errorf("image data changed: %d bytes", len(got))
+	}
+
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("extra part: %v", err)
+	}
+}
+
+func decodePart(t *testing.T, p *multipart.Part) []byte {
+	t.Helper()
+	data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,237 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/service"
+	"events-service/internal/notify"
+	"events-service/internal/render"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ses"
+	"github.com/aws/aws-sdk-go-v2/service/ses/types"
+)
+
+// SESAPI is the part of the SES client the email notifier uses.
+type SESAPI interface {
+	SendEmail(ctx context.Context, in *ses.SendEmailInput, optFns ...func(*ses.Options)) (*ses.SendEmailOutput, error)
+	SendRawEmail(ctx context.Context, in *ses.SendRawEmailInput, optFns ...func(*ses.Options)) (*ses.SendRawEmailOutput, error)
+}
+
+// EmailNotifier mails the event's audience from the tenant's SES sender.
+type EmailNotifier struct {
+	Service *service.EventService
+	SES     SESAPI
+
+	// PublicBaseURL prefixes signed attachment links; empty leaves them out.
+	PublicBaseURL string
+
+	// Concurrency caps the SES requests one job has in flight.
+	Concurrency int
+}
+
+// NewEmailNotifier sends through SES with the default AWS configuration.
+func NewEmailNotifier(ctx context.Context, svc *service.EventService, publicBaseURL string) (*EmailNotifier, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("SES load config error: %w", err)
+	}
+	return &EmailNotifier{Service: svc, SES: ses.NewFromConfig(cfg), PublicBaseURL: publicBaseURL, Concurrency: 1}, nil
+}
+
+func (n *EmailNotifier) Name() string { return "email" }
+
+// Validate accepts any payload: emails are composed from the event itself.
+func (n *EmailNotifier) Validate(map[string]any) error { return nil }
+
+func (n *EmailNotifier) Send(ctx context.Context, d notify.Delivery) error {
+	svc := n.Service.ForTenant(d.TenantID)
+	org, err := n.Service.TenantByID(d.TenantID)
+	if err != nil {
+		return fmt.Errorf("cannot load tenant: %w", err)
+	}
+	if org.SESSource == "" {
+		return notify.Permanent(fmt.Errorf("tenant %s has no SES sender configured", org.Slug))
+	}
+
+	// Load event content
+	event, err := svc.GetEvent(d.EventID)
+	if err != nil {
+		return fmt.Errorf("cannot load event for SES: %w", err)
+	}
+
+	// Only the event's audience; an empty audience is all staff
+	recipients, err := svc.GetAudienceEmails(event.Audience.Data())
+	if err != nil {
+		return fmt.Errorf("cannot load staff emails: %w", err)
+	}
+
+	if len(recipients) == 0 {
+		log.Println("SES: No recipients found")
+		return nil
+	}
+
+	// Build email
+	body := render.HTML(event.Body.Body)
+
+	// announcements show the cover inline rather than linking to it, and
+	// link to the attachments
+	var images []inlineImage
+	var links []attachmentLink
+	if d.Kind == "announcement" {
+		if img, err := coverForEmail(ctx, svc, event); err != nil {
+			log.Printf("SES: sending without cover image: %v\n", err)
+		} else if img != nil {
+			images = append(images, *img)
+		}
+		if links, err = attachmentLinks(svc, event, n.PublicBaseURL); err != nil {
+			return err
+		}
+	}
+	coverCID := ""
+	if len(images) > 0 {
+		coverCID = images[0].ContentID
+	}
+
+	subject, bodyHTML := announcementEmail(event.Title, event.Summary, body, event.ScheduledAt, coverCID, links)
+	switch d.Kind {
+	case "recall":
+		reason, _ := d.Payload["reason"].(string)
+		subject, bodyHTML = recallEmail(event.Title, reason)
+	case "update":
+		changeType, _ := d.Payload["change_type"].(string)
+		note, _ := d.Payload["note"].(string)
+		subject, bodyHTML = updateEmail(event.Title, changeType, payloadStrings(d.Payload["changes"]), note, body)
+	}
+
+	// one bad address does not fail the job; nothing getting through does
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		lastErr error
+		failed  int
+	)
+	slots := make(chan struct{}, max(n.Concurrency, 1))
+	for _, email := range recipients {
+		// a lost lease cancels ctx; stop starting sends the next worker will repeat
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(email string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := n.sendOne(ctx, org.SESSource, email, subject, bodyHTML, images); err != nil {
+				log.Printf("SES: error sending to %s: %v\n", email, err)
+				mu.Lock()
+				lastErr, failed = err, failed+1
+				mu.Unlock()
+				return
+			}
+			log.Printf("SES: email sent to %s\n", email)
+		}(email)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if failed == len(recipients) {
+		return fmt.Errorf("SES: all %d sends failed: %w", failed, lastErr)
+	}
+	return nil
+}
+
+// sendOne sends the message to one recipient, as a raw MIME message when
+// it carries inline images.
+func (n *EmailNotifier) sendOne(ctx context.Context, source, recipient, subject, bodyHTML string, images []inlineImage) error {
+	to := []string{recipient}
+
+	if len(images) > 0 {
+		raw, err := rawEmail(source, to, subject, bodyHTML, images)
+		if err != nil {
+			return notify.Permanent(fmt.Errorf("cannot build email: %w", err))
+		}
+		_, err = n.SES.SendRawEmail(ctx, &ses.SendRawEmailInput{
+			Source:       aws.String(source),
+			Destinations: to,
+			RawMessage:   &types.RawMessage{Data: raw},
+		})
+		return err
+	}
+
+	input := &ses.SendEmailInput{
+		Destination: &types.Destination{
+			ToAddresses: to,
+		},
+		Message: &types.Message{
+			Body: &types.Body{
+				Html: &types.Content{
+					Charset: aws.String("UTF-8"),
+					Data:    aws.String(bodyHTML),
+				},
+			},
+			Subject: &types.Content{
+				Charset: aws.String("UTF-8"),
+				Data:    aws.String(subject),
+			},
+		},
+		Source: aws.String(source),
+	}
+
+	_, err := n.SES.SendEmail(ctx, input)
+	return err
+}
+
+// Retryable treats rejected messages and unverified senders as permanent;
+// throttling and outages are retried.
+func (n *EmailNotifier) Retryable(err error) bool {
+	var rejected *types.MessageRejected
+	var fromUnverified *types.FromEmailAddressNotVerifiedException
+	var domainUnverified *types.MailFromDomainNotVerifiedException
+	var noConfigSet *types.ConfigurationSetDoesNotExistException
+	switch {
+	case notify.IsPermanent(err),
+		errors.As(err, &rejected),
+		errors.As(err, &fromUnverified),
+		errors.As(err, &domainUnverified),
+		errors.As(err, &noConfigSet):
+		return false
+	}
+	return true
+}
+
+// coverForEmail loads the medium rendition of the event's cover, or nil when
+// the event has no usable cover.
+func coverForEmail(ctx context.Context, svc *service.EventService, event *models.Event) (*inlineImage, error) {
+	if event.Cover == nil || event.Cover.ScanStatus != models.ScanClean {
+		return nil, nil
+	}
+	if _, ok := event.Cover.Variant("medium"); !ok {
+		return nil, nil
+	}
+
+	rc, _, err := svc.OpenVariant(ctx, event.Cover, "medium")
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	return &inlineImage{ContentID: "cover", ContentType: "image/jpeg", Data: data}, nil
+}
@@ -0,0 +1,210 @@
+package workers
+
+import (
+	"regexp"
+	"strings"
+
+	"events-service/internal/events/models"
+)
+
+// FCM allows at most five topics in one condition.
+const fcmMaxConditionTopics = 5
+
+var topicUnsafe = regexp.MustCompile(`[^a-zA-Z0-9\-_.~%]+`)
+
+// fcmTarget addresses one push message: a plain topic or a condition.
+type fcmTarget struct {
+	Topic     string
+	Condition string
+}
+
+// audienceTopic is the topic app clients subscribe to for one attribute of
+// their profile, e.g. events-location-colombo or events-user-<id>.
+func audienceTopic(base, kind, value string) string {
+	return base + "-" + kind + "-" + strings.Trim(topicUnsafe.ReplaceAllString(value, "-"), "-")
+}
+
+func inTopic(t string) string {
+	return "'" + t + "' in topics"
+}
+
+func anyOf(base, kind string, values []string) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		parts = append(parts, inTopic(audienceTopic(base, kind, v)))
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "(" + strings.Join(parts, " || ") + ")"
+}
+
+type audienceDimension struct {
+	kind   string
+	values []string
+}
+
+func dimensions(a models.Audience) []audienceDimension {
+	var dims []audienceDimension
+	for _, d := range []audienceDimension{{"department", a.Departments}, {"location", a.Locations}, {"role", a.Roles}} {
+		if len(d.values) > 0 {
+			dims = append(dims, d)
+		}
+	}
+	return dims
+}
+
+// fcmTargets returns the plain topic for everyone-audiences, or FCM
+// conditions otherwise: staff must match each dimension, and named users
+// are added on top. An audience needing more than fcmMaxConditionTopics
+// topics is split over several messages so that no device matches two of
+// them; named users must then not also match the dimensions, see
+// namedOutsideDimensions.
+func fcmTargets(base string, a models.Audience) []fcmTarget {
+	if a.Everyone() {
+		return []fcmTarget{{Topic: base}}
+	}
+
+	dims := dimensions(a)
+	topics := len(a.UserIDs)
+	for _, d := range dims {
+		topics += len(d.values)
+	}
+
+	dimension := func(chunks [][]string) string {
+		parts := make([]string, 0, len(dims))
+		for i, d := range dims {
+			parts = append(parts, anyOf(base, d.kind, chunks[i]))
+		}
+		return strings.Join(parts, " && ")
+	}
+
+	if topics <= fcmMaxConditionTopics {
+		var alternatives []string
+		if len(dims) > 0 {
+			whole := make([][]string, len(dims))
+			for i, d := range dims {
+				whole[i] = d.values
+			}
+			match := dimension(whole)
+			if len(dims) > 1 && len(a.UserIDs) > 0 {
+				match = "(" + match + ")"
+			}
+			alternatives = append(alternatives, match)
+		}
+		for _, id := range a.UserIDs {
+			alternatives = append(alternatives, inTopic(audienceTopic(base, "user", id.String())))
+		}
+		return []fcmTarget{{Condition: strings.Join(alternatives, " || ")}}
+	}
+
+	var targets []fcmTarget
+	if len(dims) > 0 {
+		// every staff member has one value per dimension, so each matches
+		// exactly one combination of chunks
+		sizes := make([]int, len(dims))
+		for i, d := range dims {
+			sizes[i] = len(d.values)
+		}
+		split := make([][][]string, len(dims))
+		for i, size := range chunkSizes(sizes) {
+			split[i] = chunk(dims[i].values, size)
+		}
+		for _, combo := range product(split) {
+			targets = append(targets, fcmTarget{Condition: dimension(combo)})
+		}
+	}
+
+	users := make([]string, 0, len(a.UserIDs))
+	for _, id := range a.UserIDs {
+		users = append(users, id.String())
+	}
+	for _, batch := range chunk(users, fcmMaxConditionTopics) {
+		alternatives := make([]string, 0, len(batch))
+		for _, id := range batch {
+			alternatives = append(alternatives, inTopic(audienceTopic(base, "user", id)))
+		}
+		targets = append(targets, fcmTarget{Condition: strings.Join(alternatives, " || ")})
+	}
+	return targets
+}
+
+// chunkSizes picks how many values of each dimension go into one condition,
+// fitting fcmMaxConditionTopics with as few messages as possible.
+func chunkSizes(lens []int) []int {
+	best, bestCount := []int(nil), 0
+	cur := make([]int, len(lens))
+
+	var try func(i, room int)
+	try = func(i, room int) {
+		if i == len(lens) {
+			count := 1
+			for j, n := range lens {
+				count *= (n + cur[j] - 1) / cur[j]
+			}
+			if best == nil || count < bestCount {
+				best, bestCount = append([]int(nil), cur...), count
+			}
+			return
+		}
+		// leave at least one topic for each later dimension
+		for size := 1; size <= min(lens[i], room-(len(lens)-1-i)); size++ {
+			cur[i] = size
+			try(i+1, room-size)
+		}
+	}
+	try(0, fcmMaxConditionTopics)
+	return best
+}
+
+func chunk(values []string, size int) [][]string {
+	if len(values) == 0 {
+		return nil
+	}
+	var out [][]string
+	for len(values) > size {
+		out = append(out, values[:size])
+		values = values[size:]
+	}
+	return append(out, values)
+}
+
+// product lists every way of picking one chunk per dimension.
+func product(split [][][]string) [][][]string {
+	combos := [][][]string{{}}
+	for _, chunks := range split {
+		var next [][][]string
+		for _, combo := range combos {
+			for _, c := range chunks {
+				next = append(next, append(append([][]string(nil), combo...), c))
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// namedOutsideDimensions drops the named users the dimensions already reach,
+// going by their directory profiles, so nobody gets the push twice once
+// named users and dimensions are sent as separate messages.
+func namedOutsideDimensions(a models.Audience, directory []models.AppUser) models.Audience {
+	if !a.HasDimensions() || len(a.UserIDs) == 0 {
+		return a
+	}
+	dims := models.Audience{Departments: a.Departments, Locations: a.Locations, Roles: a.Roles}
+
+	reached := make(map[string]bool, len(directory))
+	for _, u := range directory {
+		if dims.Includes(u) {
+			reached[u.ID.String()] = true
+		}
+	}
+	kept := a.UserIDs[:0:0]
+	for _, id := range a.UserIDs {
+		if !reached[id.String()] {
+			kept = append(kept, id)
+		}
+	}
+	a.UserIDs = kept
+	return a
+}
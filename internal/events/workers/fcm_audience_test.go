@@ -0,0 +1,87 @@
+package workers
+
+import (
+	"strings"
+	"testing"
+
+	"events-service/internal/events/models"
+
+	"github.com/google/uuid"
+)
+
+func TestFCMTargets(t *testing.T) {
+	targets := fcmTargets("events", models.Audience{})
+	if len(targets) != 1 || targets[0].Topic != "events" || targets[0].Condition != "" {
+		t.Fatalf("everyone: %+v", targets)
+	}
+
+	targets = fcmTargets("events", models.Audience{
+		Departments: []string{"engineering", "qa"},
+		Locations:   []string{"colombo"},
+	})
+	want := "('events-department-engineering' in topics || 'events-department-qa' in topics) && 'events-location-colombo' in topics"
+	if len(targets) != 1 || targets[0].Condition != want {
+		t.Fatalf("got %+v\nwant %q", targets, want)
+	}
+
+	id := uuid.MustParse("6f1c1e1a-0000-4000-8000-000000000001")
+	targets = fcmTargets("events", models.Audience{
+		Locations: []string{"new york", "london"},
+		Roles:     []string{"hr"},
+		UserIDs:   []uuid.UUID{id},
+	})
+	want = "(('events-location-new-york' in topics || 'events-location-london' in topics) && 'events-role-hr' in topics) || 'events-user-6f1c1e1a-0000-4000-8000-000000000001' in topics"
+	if len(targets) != 1 || targets[0].Condition != want {
+		t.Fatalf("got %+v\nwant %q", targets, want)
+	}
+}
+
+func TestFCMTargetsSplitBroadAudiences(t *testing.T) {
+	targets := fcmTargets("events", models.Audience{Locations: []string{"a", "b", "c", "d", "e", "f"}})
+	want := []string{
+		"('events-location-a' in topics || 'events-location-b' in topics || 'events-location-c' in topics)",
+		"('events-location-d' in topics || 'events-location-e' in topics || 'events-location-f' in topics)",
+	}
+	if len(targets) != len(want) {
+		t.Fatalf("got %d targets, want %d: %+v", len(targets), len(want), targets)
+	}
+	for i, w := range want {
+		if targets[i].Condition != w {
+			t.Errorf("target %d: got %q\nwant %q", i, targets[i].Condition, w)
+		}
+	}
+
+	// the API's limit on audience values must always fit some split
+	a := models.Audience{}
+	for i := 0; i < 20; i++ {
+		a.Departments = append(a.Departments, "d"+string(rune('a'+i)))
+		a.Locations = append(a.Locations, "l"+string(rune('a'+i)))
+	}
+	for i := 0; i < 10; i++ {
+		a.UserIDs = append(a.UserIDs, uuid.New())
+	}
+	targets = fcmTargets("events", a)
+	for _, target := range targets {
+		if n := strings.Count(target.Condition, " in topics"); n > fcmMaxConditionTopics {
+			t.Fatalf("condition has %d topics: %q", n, target.Condition)
+		}
+	}
+	// 20x20 values as 2+3 per condition is 10x7 messages, plus 2 for users
+	if len(targets) != 72 {
+		t.Fatalf("got %d targets, want 72", len(targets))
+	}
+}
+
+func TestNamedOutsideDimensions(t *testing.T) {
+	inside := models.AppUser{ID: uuid.New(), Location: "colombo"}
+	outside := models.AppUser{ID: uuid.New(), Location: "london"}
+	unknown := uuid.New()
+
+	a := namedOutsideDimensions(models.Audience{
+		Locations: []string{"colombo"},
+		UserIDs:   []uuid.UUID{inside.ID, outside.ID, unknown},
+	}, []models.AppUser{inside, outside})
+	if len(a.UserIDs) != 2 || a.UserIDs[0] != outside.ID || a.UserIDs[1] != unknown {
+		t.Fatalf("got %v", a.UserIDs)
+	}
+}
@@ -0,0 +1,190 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"events-service/internal/events/models"
+	"events-service/internal/events/service"
+	"events-service/internal/notify"
+
+	firebase "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/messaging"
+	"google.golang.org/api/option"
+)
+
+// FCMNotifier pushes through the tenant's Firebase project to the tenant's
+// topic, or to the audience's topic conditions when the event is targeted.
+type FCMNotifier struct {
+	Service *service.EventService
+
+	// PublicBaseURL prefixes the signed cover image link; empty sends
+	// notifications without images.
+	PublicBaseURL string
+
+	// one client per Firebase project, keyed by service account file
+	mu      sync.Mutex
+	clients map[string]*messaging.Client
+}
+
+func NewFCMNotifier(svc *service.EventService, publicBaseURL string) *FCMNotifier {
+	return &FCMNotifier{Service: svc, PublicBaseURL: publicBaseURL, clients: map[string]*messaging.Client{}}
+}
+
+func (n *FCMNotifier) Name() string { return "fcm" }
+
+// Validate requires the title a notification cannot be shown without.
+func (n *FCMNotifier) Validate(payload map[string]any) error {
+	if title, _ := payload["title"].(string); title == "" {
+		return errors.New("push notifications need a title")
+	}
+	return nil
+}
+
+func (n *FCMNotifier) Send(ctx context.Context, d notify.Delivery) error {
+	svc := n.Service.ForTenant(d.TenantID)
+	org, err := n.Service.TenantByID(d.TenantID)
+	if err != nil {
+		return fmt.Errorf("cannot load tenant: %w", err)
+	}
+
+	event, err := svc.GetEvent(d.EventID)
+	if err != nil {
+		return fmt.Errorf("cannot load event for FCM: %w", err)
+	}
+	audience := event.Audience.Data()
+	if audience.HasDimensions() && len(audience.UserIDs) > 0 {
+		directory, err := svc.GetAppUsers(audience.UserIDs)
+		if err != nil {
+			return fmt.Errorf("cannot load named users: %w", err)
+		}
+		audience = namedOutsideDimensions(audience, directory)
+	}
+	targets := fcmTargets(org.FCMTopic, audience)
+
+	client, err := n.client(ctx, org.FCMCredentialsFile)
+	if err != nil {
+		return err
+	}
+
+	title, _ := d.Payload["title"].(string)
+	body, _ := d.Payload["summary"].(string)
+	if body == "" {
+		// plain-text rendering of the Markdown body
+		body, _ = d.Payload["body"].(string)
+	}
+
+	notification := &messaging.Notification{
+		Title: title,
+		Body:  body,
+	}
+	if n.PublicBaseURL != "" && d.Kind != "recall" {
+		links, err := svc.LinkSigner(svc.Links.MessageTTL)
+		if err != nil {
+			return err
+		}
+		notification.ImageURL = coverImageURL(n.PublicBaseURL, links, event.Cover)
+	}
+
+	messages := make([]*messaging.Message, 0, len(targets))
+	for _, t := range targets {
+		messages = append(messages, &messaging.Message{
+			Topic:        t.Topic,
+			Condition:    t.Condition,
+			Notification: notification,
+		})
+	}
+
+	if len(messages) == 1 {
+		_, err = client.Send(ctx, messages[0])
+		return err
+	}
+	return sendEach(ctx, client, messages)
+}
+
+// fcmBatchSize is the most messages SendEach takes at once.
+const fcmBatchSize = 500
+
+// sendEach sends a split audience's messages. Like email, some getting
+// through is success; retrying would repeat them.
+func sendEach(ctx context.Context, client *messaging.Client, messages []*messaging.Message) error {
+	var lastErr error
+	failed := 0
+	for start := 0; start < len(messages); start += fcmBatchSize {
+		batch := messages[start:min(start+fcmBatchSize, len(messages))]
+		resp, err := client.SendEach(ctx, batch)
+		if err != nil {
+			lastErr, failed = err, failed+len(batch)
+			continue
+		}
+		for _, r := range resp.Responses {
+			if r.Error != nil {
+				lastErr, failed = r.Error, failed+1
+			}
+		}
+	}
+
+	if failed == len(messages) {
+		return fmt.Errorf("FCM: all %d messages failed: %w", failed, lastErr)
+	}
+	if failed > 0 {
+		log.Printf("FCM: %d of %d messages failed: %v\n", failed, len(messages), lastErr)
+	}
+	return nil
+}
+
+// Retryable treats rejected messages and credential problems as permanent;
+// outages, quota and rate errors are retried.
+func (n *FCMNotifier) Retryable(err error) bool {
+	switch {
+	case notify.IsPermanent(err),
+		messaging.IsInvalidArgument(err),
+		messaging.IsSenderIDMismatch(err),
+		messaging.IsMismatchedCredential(err),
+		messaging.IsThirdPartyAuthError(err):
+		return false
+	}
+	return true
+}
+
+func (n *FCMNotifier) client(ctx context.Context, credPath string) (*messaging.Client, error) {
+	if credPath == "" {
+		return nil, notify.Permanent(errors.New("FCM credentials file not configured"))
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if client, ok := n.clients[credPath]; ok {
+		return client, nil
+	}
+
+	app, err := firebase.NewApp(ctx, nil, option.WithCredentialsFile(credPath))
+	if err != nil {
+		return nil, err
+	}
+	client, err := app.Messaging(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("FCM client initialized for %s ✔\n", credPath)
+
+	n.clients[credPath] = client
+	return client, nil
+}
+
+// coverImageURL is the signed public address of the cover's medium
+// rendition, or "" when the event has no usable cover.
+func coverImageURL(publicBaseURL string, links *service.LinkSigner, cover *models.Attachment) string {
+	if cover == nil || cover.ScanStatus != models.ScanClean {
+		return ""
+	}
+	if _, ok := cover.Variant("medium"); !ok {
+		return ""
+	}
+	return publicBaseURL + links.Sign(service.CoverPath(cover.ID, "medium"), nil)
+}
@@ -0,0 +1,93 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"events-service/internal/notify"
+
+	"github.com/aws/aws-sdk-go-v2/service/ses"
+	"github.com/aws/aws-sdk-go-v2/service/ses/types"
+)
+
+// recordingSES remembers the destinations of every send.
+type recordingSES struct {
+	to [][]string
+}
+
+func (r *recordingSES) SendEmail(_ context.Context, in *ses.SendEmailInput, _ ...func(*ses.Options)) (*ses.SendEmailOutput, error) {
+	r.to = append(r.to, in.Destination.ToAddresses)
+	return &ses.SendEmailOutput{}, nil
+}
+
+func (r *recordingSES) SendRawEmail(_ context.Context, in *ses.SendRawEmailInput, _ ...func(*ses.Options)) (*ses.SendRawEmailOutput, error) {
+	r.to = append(r.to, in.Destinations)
+	return &ses.SendRawEmailOutput{}, nil
+}
+
+func TestNotifierErrorClassification(t *testing.T) {
+	email := &EmailNotifier{}
+	if email.Retryable(fmt.Errorf("SES: all 3 sends failed: %w", &types.MessageRejected{})) {
+		t.Error("rejected email retried")
+	}
+	if !email.Retryable(errors.New("connection reset")) {
+		t.Error("network error not retried")
+	}
+
+	fcm := NewFCMNotifier(nil, "")
+	if fcm.Retryable(notify.Permanent(errors.New("bad config"))) {
+		t.Error("permanent FCM error retried")
+	}
+	if err := fcm.Validate(map[string]any{"summary": "no title"}); err == nil {
+		t.Error("push without title accepted")
+	}
+}
+
+func TestEmailGoesToTheRecipient(t *testing.T) {
+	fake := &recordingSES{}
+	n := &EmailNotifier{SES: fake}
+	ctx := context.Background()
+
+	if err := n.sendOne(ctx, "events@example.com", "ana@example.com", "Hi", "<p>Hi</p>", nil); err != nil {
+		t.Fatal(err)
+	}
+	cover := []inlineImage{{ContentID: "cover", ContentType: "image/jpeg", Data: []byte{0xff, 0xd8}}}
+	if err := n.sendOne(ctx, "events@example.com", "raj@example.com", "Hi", "<p>Hi</p>", cover); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.to) != 2 || fake.to[0][0] != "ana@example.com" || fake.to[1][0] != "raj@example.com" {
+		t.Errorf("sent to %v", fake.to)
+	}
+}
+
+func TestTeamsTextFollowsTheKind(t *testing.T) {
+	links := []attachmentLink{{Name: "agenda.pdf", URL: "https://events.example.com/a?sig=x"}}
+
+	text := teamsText(notify.Delivery{Kind: "announcement"}, "Picnic", "Bring **food**", links)
+	if !strings.HasPrefix(text, "**Picnic**") || !strings.Contains(text, "agenda.pdf") {
+		t.Errorf("announcement: %q", text)
+	}
+
+	text = teamsText(notify.Delivery{Kind: "recall", Payload: map[string]any{
+		"title": "Withdrawn: Picnic", "summary": "rain", "reason": "rain",
+	}}, "Picnic", "Bring **food**", links)
+	if !strings.HasPrefix(text, "**Withdrawn: Picnic**") || !strings.Contains(text, "Reason: rain") {
+		t.Errorf("recall: %q", text)
+	}
+	if strings.Contains(text, "food") || strings.Contains(text, "agenda.pdf") {
+		t.Errorf("recall repeats the withdrawn announcement: %q", text)
+	}
+
+	text = teamsText(notify.Delivery{Kind: "update", Payload: map[string]any{
+		"title":   "Correction: Picnic",
+		"summary": "Title changed",
+		"changes": []any{"Rescheduled to Sat, 06 Jun 2026 10:00:00 UTC"},
+	}}, "Picnic", "Bring food", nil)
+	if !strings.HasPrefix(text, "**Correction: Picnic**") || !strings.Contains(text, "- Rescheduled") || !strings.Contains(text, "Bring food") {
+		t.Errorf("update: %q", text)
+	}
+}
@@ -0,0 +1,69 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"events-service/internal/events/service"
+)
+
+// ScanWorker retries malware scans for attachments still pending, such as
+// those uploaded while the scanner was unreachable, and renders images once
+// they scan clean, outside the upload request. It runs unscoped, so it
+// covers every tenant.
+type ScanWorker struct {
+	Service      *service.EventService
+	PollInterval time.Duration
+	BatchSize    int
+	StopCh       chan struct{}
+}
+
+func NewScanWorker(svc *service.EventService) *ScanWorker {
+	return &ScanWorker{
+		Service:      svc,
+		PollInterval: 30 * time.Second,
+		BatchSize:    20,
+		StopCh:       make(chan struct{}),
+	}
+}
+
+func (w *ScanWorker) Start() {
+	go func() {
+		log.Println("ScanWorker: started")
+		for {
+			select {
+			case <-w.StopCh:
+				log.Println("ScanWorker: stopping")
+				return
+			default:
+				w.runOnce()
+				time.Sleep(w.PollInterval)
+			}
+		}
+	}()
+}
+
+func (w *ScanWorker) Stop() {
+	close(w.StopCh)
+}
+
+func (w *ScanWorker) runOnce() {
+	n, err := w.Service.ScanPendingAttachments(context.Background(), w.BatchSize)
+	if err != nil {
+		log.Printf("ScanWorker: %v\n", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("ScanWorker: scanned %d attachments\n", n)
+	}
+
+	n, err = w.Service.MakePendingVariants(context.Background(), w.BatchSize)
+	if err != nil {
+		log.Printf("ScanWorker: %v\n", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("ScanWorker: resized %d images\n", n)
+	}
+}
@@ -0,0 +1,100 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"events-service/internal/events/service"
+	"events-service/internal/notify"
+	"events-service/internal/render"
+)
+
+// TeamsNotifier posts the event to Teams. Delivery is still a stub that
+// logs the card text.
+type TeamsNotifier struct {
+	Service *service.EventService
+
+	// PublicBaseURL prefixes signed attachment links; empty leaves them out.
+	PublicBaseURL string
+}
+
+func NewTeamsNotifier(svc *service.EventService, publicBaseURL string) *TeamsNotifier {
+	return &TeamsNotifier{Service: svc, PublicBaseURL: publicBaseURL}
+}
+
+func (n *TeamsNotifier) Name() string { return "teams" }
+
+func (n *TeamsNotifier) Validate(map[string]any) error { return nil }
+
+func (n *TeamsNotifier) Send(ctx context.Context, d notify.Delivery) error {
+	svc := n.Service.ForTenant(d.TenantID)
+	event, err := svc.GetEvent(d.EventID)
+	if err != nil {
+		return fmt.Errorf("cannot load event for Teams: %w", err)
+	}
+
+	// only announcements link to the attachments; a recall must not hand
+	// out fresh links to what it withdraws
+	var links []attachmentLink
+	if d.Kind == "announcement" {
+		if links, err = attachmentLinks(svc, event, n.PublicBaseURL); err != nil {
+			return err
+		}
+	}
+	text := teamsText(d, event.Title, event.Body.Body, links)
+
+	log.Printf("sendTeams: event=%v payload=%v text=%q (stub)\n", d.EventID, d.Payload, text)
+	return nil
+}
+
+// teamsText is the card text for a delivery: the announcement with its
+// attachments, a recall with its reason, or an update listing what changed
+// above the current text.
+func teamsText(d notify.Delivery, title, body string, links []attachmentLink) string {
+	heading, _ := d.Payload["title"].(string)
+	summary, _ := d.Payload["summary"].(string)
+
+	switch d.Kind {
+	case "recall":
+		if heading == "" {
+			heading = "Withdrawn: " + title
+		}
+		reason, _ := d.Payload["reason"].(string)
+		if reason == "" {
+			reason = summary
+		}
+		text := "**" + heading + "**\n\nThis announcement has been withdrawn. Please disregard it."
+		if reason != "" {
+			text += "\n\nReason: " + reason
+		}
+		return text
+	case "update":
+		if heading == "" {
+			heading = "Updated: " + title
+		}
+		text := "**" + heading + "**"
+		if changes := payloadStrings(d.Payload["changes"]); len(changes) > 0 {
+			if note, _ := d.Payload["note"].(string); note != "" {
+				text += "\n\n" + note
+			}
+			for _, c := range changes {
+				text += "\n- " + c
+			}
+		} else if summary != "" {
+			text += "\n\n" + summary
+		}
+		return text + "\n\n---\n\n" + render.Teams(body)
+	}
+
+	text := "**" + title + "**\n\n" + render.Teams(body)
+	if len(links) > 0 {
+		text += "\n\n**Attachments**"
+		for _, l := range links {
+			text += "\n- [" + l.Name + "](" + l.URL + ")"
+		}
+	}
+	return text
+}
+
+func (n *TeamsNotifier) Retryable(err error) bool { return !notify.IsPermanent(err) }
@@ -0,0 +1,61 @@
+// Package httpsec holds the HTTP hardening applied in front of every route:
+// CORS and standard security headers.
+package httpsec
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORSOptions is the per-environment CORS policy.
+type CORSOptions struct {
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// allowHeaders are the request headers browsers may send cross-origin.
+var allowHeaders = []string{"Origin", "Content-Type", "Authorization", "X-API-Key", "X-Request-ID"}
+
+// exposeHeaders lets browser clients read rate-limit and request-id headers.
+var exposeHeaders = []string{
+	"Content-Length", "ETag",
+	"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After",
+	"X-Request-ID",
+}
+
+// CORS returns the CORS middleware, or nil when no origins are allowed and
+// the API is same-origin only.
+func CORS(opts CORSOptions) (gin.HandlerFunc, error) {
+	if len(opts.AllowOrigins) == 0 {
+		return nil, nil
+	}
+
+	for _, o := range opts.AllowOrigins {
+		if o == "*" && opts.AllowCredentials {
+			return nil, errors.New("cors: wildcard origin cannot be combined with credentials")
+		}
+	}
+
+	cfg := cors.Config{
+		AllowMethods:     opts.AllowMethods,
+		AllowHeaders:     allowHeaders,
+		ExposeHeaders:    exposeHeaders,
+		AllowCredentials: opts.AllowCredentials,
+		MaxAge:           opts.MaxAge,
+	}
+	if len(opts.AllowOrigins) == 1 && opts.AllowOrigins[0] == "*" {
+		cfg.AllowAllOrigins = true
+	} else {
+		cfg.AllowOrigins = opts.AllowOrigins
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cors.New(cfg), nil
+}
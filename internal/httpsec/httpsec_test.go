@@ -0,0 +1,82 @@
+package httpsec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newRouter(mw ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(mw...)
+	r.GET("/ping", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+	return r
+}
+
+func TestHeaders(t *testing.T) {
+	r := newRouter(Headers(HeaderOptions{HSTSMaxAge: 24 * time.Hour, ContentSecurityPolicy: "default-src 'none'"}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Fatalf("nosniff: %q", got)
+	}
+	if got := w.Header().Get("Content-Security-Policy"); got != "default-src 'none'" {
+		t.Fatalf("csp: %q", got)
+	}
+	if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Fatalf("hsts over plain http: %q", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=86400; includeSubDomains" {
+		t.Fatalf("hsts: %q", got)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	mw, err := CORS(CORSOptions{
+		AllowOrigins:     []string{"https://staff.example.com"},
+		AllowMethods:     []string{"GET", "POST"},
+		AllowCredentials: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := newRouter(mw)
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("Origin", "https://staff.example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://staff.example.com" {
+		t.Fatalf("allow origin: %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Expose-Headers"); got == "" {
+		t.Fatal("expected exposed headers")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("foreign origin: status %d", w.Code)
+	}
+}
+
+func TestCORSRejectsWildcardWithCredentials(t *testing.T) {
+	if _, err := CORS(CORSOptions{AllowOrigins: []string{"*"}, AllowCredentials: true}); err == nil {
+		t.Fatal("expected error")
+	}
+	if mw, err := CORS(CORSOptions{}); mw != nil || err != nil {
+		t.Fatalf("no origins should disable CORS, got %v %v", mw, err)
+	}
+}
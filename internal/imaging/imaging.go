@@ -0,0 +1,89 @@
+// Package imaging turns uploaded pictures into the fixed-width JPEG
+// renditions used for event cover images.
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels bounds the decoded size, so a small file that claims huge
+// dimensions cannot exhaust memory.
+const MaxPixels = 40_000_000
+
+var ErrTooLarge = errors.New("imaging: image dimensions too large")
+
+// Size is one rendition, scaled down to MaxWidth and never up.
+type Size struct {
+	Name     string
+	MaxWidth int
+}
+
+// CoverSizes are the renditions made for every uploaded image: a feed
+// thumbnail, a push and email size, and a full-width detail image.
+var CoverSizes = []Size{
+	{Name: "thumb", MaxWidth: 320},
+	{Name: "medium", MaxWidth: 800},
+	{Name: "large", MaxWidth: 1600},
+}
+
+// Decodable reports whether mimeType is a format Decode understands.
+func Decodable(mimeType string) bool {
+	switch mimeType {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+// Decode reads a PNG, JPEG, GIF or WebP image after checking its
+// dimensions against MaxPixels.
+func Decode(r io.ReadSeeker) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, fmt.Errorf("imaging: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("imaging: %w", err)
+	}
+	return img, nil
+}
+
+// Fit scales src down to maxWidth, keeping its aspect ratio, onto a white
+// background so transparent areas do not turn black as JPEG.
+func Fit(src image.Image, maxWidth int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > maxWidth {
+		h = max(1, h*maxWidth/w)
+		w = maxWidth
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Over, nil)
+	return dst
+}
+
+// EncodeJPEG writes img at a quality suited to photos on screens.
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: 82})
+}
@@ -0,0 +1,72 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func pngOf(t *testing.T, w, h int) *bytes.Reader {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.NRGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestDecodeFitEncode(t *testing.T) {
+	src, err := Decode(pngOf(t, 1000, 500))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thumb := Fit(src, 320)
+	if b := thumb.Bounds(); b.Dx() != 320 || b.Dy() != 160 {
+		t.Fatalf("thumb is %v", b)
+	}
+
+	// narrower than the limit: kept as is
+	if b := Fit(src, 1600).Bounds(); b.Dx() != 1000 || b.Dy() != 500 {
+		t.Fatalf("large is %v", b)
+	}
+
+	var out bytes.Buffer
+	if err := EncodeJPEG(&out, thumb); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jpeg.Decode(&out); err != nil {
+		t.Fatalf("not a jpeg: %v", err)
+	}
+}
+
+func TestFitFlattensTransparency(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 10, 10)) // fully transparent
+	r, g, b, _ := Fit(src, 10).At(5, 5).RGBA()
+	if r>>8 != 255 || g>>8 != 255 || b>>8 != 255 {
+		t.Fatalf("transparent pixel became %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestDecodeRejectsHugeDimensions(t *testing.T) {
+	// a PNG header announcing 100000x100000 pixels
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1)))
+	raw := buf.Bytes()
+	copy(raw[16:24], []byte{0, 1, 0x86, 0xa0, 0, 1, 0x86, 0xa0})
+	binary.BigEndian.PutUint32(raw[29:33], crc32.ChecksumIEEE(raw[12:29]))
+
+	if _, err := Decode(bytes.NewReader(raw)); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+}
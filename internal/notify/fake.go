@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+// Fake records deliveries instead of sending them, failing with Err when
+// it is set. Wrap Err with Permanent to test the no-retry path.
+type Fake struct {
+	Channel string
+	Err     error
+
+	mu   sync.Mutex
+	sent []Delivery
+}
+
+func NewFake(channel string) *Fake {
+	return &Fake{Channel: channel}
+}
+
+func (f *Fake) Name() string { return f.Channel }
+
+func (f *Fake) Validate(map[string]any) error { return nil }
+
+func (f *Fake) Send(_ context.Context, d Delivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.sent = append(f.sent, d)
+	return nil
+}
+
+func (f *Fake) Retryable(err error) bool { return !IsPermanent(err) }
+
+// Sent returns the deliveries that went through.
+func (f *Fake) Sent() []Delivery {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Delivery(nil), f.sent...)
+}
@@ -0,0 +1,125 @@
+// Package notify defines the delivery channels the broadcast worker sends
+// through, and the registry they are configured in at startup.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnknownChannel = errors.New("unknown channel")
+
+// Delivery is one queued broadcast job as a notifier sees it.
+type Delivery struct {
+	JobID    int
+	TenantID uuid.UUID
+	EventID  uuid.UUID
+	Kind     string // announcement | recall | update
+	Payload  map[string]any
+	Attempt  int // 1 on the first try
+}
+
+// Notifier sends deliveries over one channel.
+type Notifier interface {
+	// Name is the channel name jobs are queued under, e.g. "email".
+	Name() string
+	// Validate rejects a payload the channel cannot send, before it is
+	// queued.
+	Validate(payload map[string]any) error
+	Send(ctx context.Context, d Delivery) error
+	// Retryable reports whether a failed send may succeed if tried again.
+	Retryable(err error) bool
+}
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as one that retrying cannot fix, such as missing
+// configuration or a message the provider rejects.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+// Registry holds the notifiers for the channels this deployment supports,
+// each with its retry policy.
+type Registry struct {
+	notifiers map[string]Notifier
+	policies  map[string]RetryPolicy
+}
+
+func NewRegistry() *Registry {
+	return &Registry{notifiers: map[string]Notifier{}, policies: map[string]RetryPolicy{}}
+}
+
+// Register adds n, retried according to policy; two notifiers cannot share
+// a name.
+func (r *Registry) Register(n Notifier, policy RetryPolicy) error {
+	if _, ok := r.notifiers[n.Name()]; ok {
+		return fmt.Errorf("notify: channel %q registered twice", n.Name())
+	}
+	r.notifiers[n.Name()] = n
+	r.policies[n.Name()] = policy
+	return nil
+}
+
+// Policy returns the channel's retry policy, or DefaultRetryPolicy for
+// channels that are not registered.
+func (r *Registry) Policy(channel string) RetryPolicy {
+	if p, ok := r.policies[channel]; ok {
+		return p
+	}
+	return DefaultRetryPolicy
+}
+
+// Get returns the notifier for channel, or ErrUnknownChannel.
+func (r *Registry) Get(channel string) (Notifier, error) {
+	if n, ok := r.notifiers[channel]; ok {
+		return n, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownChannel, channel)
+}
+
+// Has reports whether a notifier is registered for channel.
+func (r *Registry) Has(channel string) bool {
+	_, ok := r.notifiers[channel]
+	return ok
+}
+
+// Names lists the registered channels in a stable order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.notifiers))
+	for name := range r.notifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks that every channel is registered and accepts payload.
+func (r *Registry) Validate(channels []string, payload map[string]any) error {
+	for _, ch := range channels {
+		n, err := r.Get(ch)
+		if err != nil {
+			return err
+		}
+		if err := n.Validate(payload); err != nil {
+			return fmt.Errorf("%s: %w", ch, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	for _, name := range []string{"teams", "email", "fcm"} {
+		if err := r.Register(NewFake(name), DefaultRetryPolicy); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Register(NewFake("email"), DefaultRetryPolicy); err == nil {
+		t.Error("duplicate channel accepted")
+	}
+
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"email", "fcm", "teams"}) {
+		t.Errorf("names: %v", got)
+	}
+	if !r.Has("fcm") || r.Has("sms") {
+		t.Error("has: wrong answer for fcm or sms")
+	}
+	if _, err := r.Get("sms"); !errors.Is(err, ErrUnknownChannel) {
+		t.Errorf("get sms: %v", err)
+	}
+	if err := r.Validate([]string{"email", "fcm"}, nil); err != nil {
+		t.Errorf("validate: %v", err)
+	}
+	if err := r.Validate([]string{"email", "pager"}, nil); !errors.Is(err, ErrUnknownChannel) {
+		t.Errorf("validate pager: %v", err)
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	base := errors.New("sender not verified")
+	err := fmt.Errorf("email: %w", Permanent(base))
+	if !IsPermanent(err) || !errors.Is(err, base) {
+		t.Errorf("wrapped permanent error lost: %v", err)
+	}
+	if IsPermanent(base) || Permanent(nil) != nil {
+		t.Error("plain errors are retryable")
+	}
+
+	f := NewFake("email")
+	if f.Retryable(err) || !f.Retryable(base) {
+		t.Error("fake classification")
+	}
+}
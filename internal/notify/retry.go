@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy decides how often and how long apart a channel's failed
+// deliveries are retried.
+type RetryPolicy struct {
+	MaxAttempts int           // including the first try
+	BaseDelay   time.Duration // before the second try; doubled for each one after
+	MaxDelay    time.Duration
+	Jitter      float64 // fraction of each delay taken off at random, 0 to 1
+}
+
+// DefaultRetryPolicy applies to channels registered without their own.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   30 * time.Second,
+	MaxDelay:    30 * time.Minute,
+	Jitter:      0.2,
+}
+
+// ParseRetryPolicy reads "attempts/base/max", e.g. "5/30s/30m", with
+// DefaultRetryPolicy's jitter.
+func ParseRetryPolicy(spec string) (RetryPolicy, error) {
+	parts := strings.Split(strings.TrimSpace(spec), "/")
+	if len(parts) != 3 {
+		return RetryPolicy{}, fmt.Errorf("retry policy %q: want attempts/base/max", spec)
+	}
+
+	attempts, err := strconv.Atoi(parts[0])
+	if err != nil || attempts <= 0 {
+		return RetryPolicy{}, fmt.Errorf("retry policy %q: bad attempts", spec)
+	}
+	base, err := time.ParseDuration(parts[1])
+	if err != nil || base <= 0 {
+		return RetryPolicy{}, fmt.Errorf("retry policy %q: bad base delay", spec)
+	}
+	maxDelay, err := time.ParseDuration(parts[2])
+	if err != nil || maxDelay < base {
+		return RetryPolicy{}, fmt.Errorf("retry policy %q: max delay must be at least the base delay", spec)
+	}
+
+	return RetryPolicy{MaxAttempts: attempts, BaseDelay: base, MaxDelay: maxDelay, Jitter: DefaultRetryPolicy.Jitter}, nil
+}
+
+// Exhausted reports whether a job that has failed attempts times should
+// stop being retried.
+func (p RetryPolicy) Exhausted(attempts int) bool {
+	return attempts >= max(p.MaxAttempts, 1)
+}
+
+// Delay is the wait after the given failed attempt, 1 for the first.
+// Jitter spreads out retries of jobs that failed together.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	return p.delay(attempt, rand.Float64())
+}
+
+func (p RetryPolicy) delay(attempt int, r float64) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	jitter := min(max(p.Jitter, 0), 1)
+	return d - time.Duration(float64(d)*jitter*r)
+}
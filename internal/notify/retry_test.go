@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: 5 * time.Second, Jitter: 0.5}
+
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 60: 5 * time.Second} {
+		if got := p.delay(attempt, 0); got != want {
+			t.Errorf("attempt %d: %v, want %v", attempt, got, want)
+		}
+	}
+	if got := p.delay(3, 1); got != 2*time.Second {
+		t.Errorf("full jitter on 4s: %v", got)
+	}
+	for i := 0; i < 100; i++ {
+		if d := p.Delay(2); d < time.Second || d > 2*time.Second {
+			t.Fatalf("delay out of range: %v", d)
+		}
+	}
+
+	if p.Exhausted(3) || !p.Exhausted(4) {
+		t.Error("exhausted")
+	}
+	if !(RetryPolicy{}).Exhausted(1) {
+		t.Error("zero policy tries once")
+	}
+}
+
+func TestParseRetryPolicy(t *testing.T) {
+	p, err := ParseRetryPolicy("6/1m/2h")
+	if err != nil || p.MaxAttempts != 6 || p.BaseDelay != time.Minute || p.MaxDelay != 2*time.Hour {
+		t.Fatalf("got %+v %v", p, err)
+	}
+	for _, bad := range []string{"", "5/30s", "0/30s/1m", "5/x/1m", "5/1h/1m"} {
+		if _, err := ParseRetryPolicy(bad); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
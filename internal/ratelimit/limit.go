@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket: Burst tokens at most, refilled at Rate per second.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the outcome of taking one token from a bucket.
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // until the next token, when not allowed
+	ResetAfter time.Duration // until the bucket is full again
+}
+
+// Store keeps bucket state. MemoryStore suits a single instance; PostgresStore
+// shares buckets between instances.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// ParseLimit reads "count/unit" with an optional ":burst", e.g. "30/m" or
+// "600/h:20". Units are s, m and h. Burst defaults to count.
+func ParseLimit(spec string) (Limit, error) {
+	spec = strings.TrimSpace(spec)
+
+	rateSpec, burstSpec, hasBurst := strings.Cut(spec, ":")
+	countStr, unit, ok := strings.Cut(rateSpec, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q: want count/unit", spec)
+	}
+
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: bad count", spec)
+	}
+
+	var per time.Duration
+	switch unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("rate limit %q: unit must be s, m or h", spec)
+	}
+
+	burst := count
+	if hasBurst {
+		burst, err = strconv.Atoi(burstSpec)
+		if err != nil || burst <= 0 {
+			return Limit{}, fmt.Errorf("rate limit %q: bad burst", spec)
+		}
+	}
+
+	return Limit{Rate: float64(count) / per.Seconds(), Burst: burst}, nil
+}
+
+// take applies one request to a bucket holding tokens at last, returning the
+// new token count and the decision.
+func take(tokens float64, last time.Time, limit Limit, now time.Time) (float64, Result) {
+	elapsed := now.Sub(last).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	tokens += elapsed * limit.Rate
+	if tokens > float64(limit.Burst) {
+		tokens = float64(limit.Burst)
+	}
+
+	res := Result{}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+
+	res.Remaining = int(tokens)
+	res.ResetAfter = secondsToDuration((float64(limit.Burst) - tokens) / limit.Rate)
+	return tokens, res
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
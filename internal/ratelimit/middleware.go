@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"events-service/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientKey identifies the caller for rate limiting: the authenticated user
+// or API key when there is one, otherwise the client IP. Limits mounted
+// before authentication therefore count per IP.
+func ClientKey(c *gin.Context) string {
+	if p, ok := auth.FromContext(c); ok {
+		if p.Kind == auth.PrincipalAPIKey {
+			return "key:" + p.Subject.String()
+		}
+		return "user:" + p.Subject.String()
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// Middleware limits requests per caller within a named route group. Store
+// errors let the request through rather than failing the API.
+func Middleware(store Store, group string, limit Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		now := time.Now()
+
+		res, err := store.Take(c.Request.Context(), group+":"+ClientKey(c), limit, now)
+		if err != nil {
+			log.Printf("RateLimit: store error for group %s: %v\n", group, err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(now.Add(res.ResetAfter).Unix(), 10))
+
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so every
+// instance shares them. Refill and take happen in one upsert, timed by the
+// database clock: instances' clocks drift apart, and a bucket refilled by
+// one and taken from by another would gain or lose the difference.
+// The now passed to Take only paces this instance's sweeps.
+type PostgresStore struct {
+	DB *gorm.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{DB: db}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.sweep(now)
+
+	var row struct {
+		Tokens  float64
+		Allowed bool
+	}
+
+	err := s.DB.WithContext(ctx).Raw(`
+		INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+		VALUES (@key, CAST(@burst AS DOUBLE PRECISION) - 1, TRUE, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			allowed = LEAST(CAST(@burst AS DOUBLE PRECISION),
+				b.tokens + GREATEST(EXTRACT(EPOCH FROM (EXCLUDED.updated_at - b.updated_at))::float8, 0) * CAST(@rate AS DOUBLE PRECISION)) >= 1,
+			tokens = LEAST(CAST(@burst AS DOUBLE PRECISION),
+				b.tokens + GREATEST(EXTRACT(EPOCH FROM (EXCLUDED.updated_at - b.updated_at))::float8, 0) * CAST(@rate AS DOUBLE PRECISION))
+				- CASE WHEN LEAST(CAST(@burst AS DOUBLE PRECISION),
+					b.tokens + GREATEST(EXTRACT(EPOCH FROM (EXCLUDED.updated_at - b.updated_at))::float8, 0) * CAST(@rate AS DOUBLE PRECISION)) >= 1
+				THEN 1 ELSE 0 END,
+			updated_at = EXCLUDED.updated_at
+		RETURNING tokens, allowed
+	`,
+		sql.Named("key", key),
+		sql.Named("burst", float64(limit.Burst)),
+		sql.Named("rate", limit.Rate),
+	).Scan(&row).Error
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{
+		Allowed:    row.Allowed,
+		Remaining:  int(row.Tokens),
+		ResetAfter: secondsToDuration((float64(limit.Burst) - row.Tokens) / limit.Rate),
+	}
+	if !row.Allowed {
+		res.RetryAfter = secondsToDuration((1 - row.Tokens) / limit.Rate)
+	}
+	return res, nil
+}
+
+// sweep deletes buckets idle for an hour, as MemoryStore does, at most once
+// a minute per instance. It runs beside the request rather than in it.
+func (s *PostgresStore) sweep(now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < time.Minute {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	go func() {
+		if err := s.Prune(context.Background(), time.Hour); err != nil {
+			log.Printf("RateLimit: cannot prune buckets: %v\n", err)
+		}
+	}()
+}
+
+// Prune deletes buckets unused for idle, by the database clock.
+func (s *PostgresStore) Prune(ctx context.Context, idle time.Duration) error {
+	return s.DB.WithContext(ctx).
+		Exec("DELETE FROM rate_limit_buckets WHERE updated_at < NOW() - make_interval(secs => ?)", idle.Seconds()).Error
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	l, err := ParseLimit("30/m")
+	require.NoError(t, err)
+	assert.Equal(t, 30, l.Burst)
+	assert.InDelta(t, 0.5, l.Rate, 1e-9)
+
+	l, err = ParseLimit("600/h:20")
+	require.NoError(t, err)
+	assert.Equal(t, 20, l.Burst)
+	assert.InDelta(t, 600.0/3600, l.Rate, 1e-9)
+
+	for _, bad := range []string{"", "30", "x/m", "30/d", "30/m:0", "-1/s"} {
+		_, err := ParseLimit(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Rate: 1, Burst: 2}
+	now := time.Now()
+	ctx := context.Background()
+
+	res, _ := store.Take(ctx, "k", limit, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+
+	res, _ = store.Take(ctx, "k", limit, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	res, _ = store.Take(ctx, "k", limit, now)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+
+	// other keys have their own bucket
+	res, _ = store.Take(ctx, "other", limit, now)
+	assert.True(t, res.Allowed)
+
+	res, _ = store.Take(ctx, "k", limit, now.Add(1500*time.Millisecond))
+	assert.True(t, res.Allowed)
+}
+
+func TestMiddlewareHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.POST("/events", Middleware(NewMemoryStore(), "events-write", Limit{Rate: 0.5, Burst: 1}), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events", nil))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("X-RateLimit-Reset"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+}
+
+func TestMiddlewareCountsPerIPBeforeAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/events", Middleware(NewMemoryStore(), "ip", Limit{Rate: 0.5, Burst: 1}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	get := func(ip string) int {
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, get("192.0.2.1"))
+	assert.Equal(t, http.StatusTooManyRequests, get("192.0.2.1"))
+	assert.Equal(t, http.StatusOK, get("192.0.2.2"))
+}
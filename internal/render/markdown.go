@@ -0,0 +1,91 @@
+// Package render turns Markdown announcement bodies into the form each
+// delivery channel needs. Bodies are rendered once per request or job so
+// every client shows the same output.
+package render
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Rendered holds one body in every channel format.
+type Rendered struct {
+	HTML  string `json:"html"`  // sanitized, for email and web
+	Text  string `json:"text"`  // plain text, for push
+	Teams string `json:"teams"` // Markdown subset Teams cards understand
+}
+
+var (
+	md = goldmark.New(goldmark.WithExtensions(
+		extension.Strikethrough,
+		extension.Table,
+		extension.Linkify,
+	))
+
+	policyOnce sync.Once
+	policy     *bluemonday.Policy
+)
+
+// sanitizer allows user-generated-content markup and forces external links
+// to open safely. Raw HTML is already dropped by goldmark; this is the
+// second line of defence for anything that slips through as a link or
+// attribute.
+func sanitizer() *bluemonday.Policy {
+	policyOnce.Do(func() {
+		policy = bluemonday.UGCPolicy()
+		policy.RequireNoFollowOnLinks(true)
+		policy.AddTargetBlankToFullyQualifiedLinks(true)
+	})
+	return policy
+}
+
+// Markdown renders src for every channel.
+func Markdown(src string) Rendered {
+	return Rendered{
+		HTML:  HTML(src),
+		Text:  Text(src),
+		Teams: Teams(src),
+	}
+}
+
+// HTML renders src to sanitized HTML.
+func HTML(src string) string {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(src), &buf); err != nil {
+		return "<p>" + bluemonday.StrictPolicy().Sanitize(src) + "</p>"
+	}
+	return strings.TrimSpace(sanitizer().Sanitize(buf.String()))
+}
+
+// Text renders src to plain text with Markdown syntax removed.
+func Text(src string) string {
+	return walk(src, textStyle)
+}
+
+// Teams renders src to the Markdown subset supported by Teams message
+// cards: emphasis, links, lists and code. Headings become bold lines and
+// raw HTML is dropped.
+func Teams(src string) string {
+	return walk(src, teamsStyle)
+}
+
+// Truncate shortens plain text to at most n runes, ending on a word
+// boundary where possible.
+func Truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	cut := string(r[:n-1])
+	if next := r[n-1]; next != ' ' && next != '\n' {
+		if i := strings.LastIndexAny(cut, " \n"); i > len(cut)/2 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRight(cut, " \n.,;:") + "…"
+}
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+const sample = `# Office move
+
+The **Colombo** office moves on *Monday*. See [the plan](https://intranet.example.com/move).
+
+- pack your desk
+- return badges
+  - visitors too
+
+<script>alert("x")</script>
+`
+
+func TestHTMLSanitizes(t *testing.T) {
+	out := HTML(sample + "\n[click](javascript:alert(1))\n\n<img src=x onerror=alert(1)>")
+
+	for _, bad := range []string{"<script", "onerror", "javascript:"} {
+		if strings.Contains(out, bad) {
+			t.Fatalf("unsanitized %q in %s", bad, out)
+		}
+	}
+	for _, want := range []string{"<h1>Office move</h1>", "<strong>Colombo</strong>", `href="https://intranet.example.com/move"`, "<li>pack your desk</li>"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in %s", want, out)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	out := Text(sample)
+
+	for _, want := range []string{"Office move", "The Colombo office moves on Monday. See the plan (https://intranet.example.com/move).", "- pack your desk", "  - visitors too"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	for _, bad := range []string{"**", "<script", "alert"} {
+		if strings.Contains(out, bad) {
+			t.Fatalf("unexpected %q in:\n%s", bad, out)
+		}
+	}
+}
+
+func TestTeams(t *testing.T) {
+	out := Teams(sample + "\n[click](javascript:alert(1))")
+
+	for _, want := range []string{"**Office move**", "**Colombo**", "*Monday*", "[the plan](https://intranet.example.com/move)", "- return badges"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "javascript:") || strings.Contains(out, "<script") {
+		t.Fatalf("unsafe markup in:\n%s", out)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("short", 10); got != "short" {
+		t.Fatalf("got %q", got)
+	}
+	got := Truncate("the quick brown fox jumps over the lazy dog", 20)
+	if got != "the quick brown fox…" {
+		t.Fatalf("got %q", got)
+	}
+}
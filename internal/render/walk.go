@@ -0,0 +1,219 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+type style int
+
+const (
+	textStyle style = iota
+	teamsStyle
+)
+
+// walker writes a goldmark AST back out as plain text or Teams Markdown.
+type walker struct {
+	src   []byte
+	style style
+	out   strings.Builder
+}
+
+func walk(src string, s style) string {
+	b := []byte(src)
+	doc := md.Parser().Parse(text.NewReader(b))
+
+	w := &walker{src: b, style: s}
+	w.blocks(doc, "")
+	return strings.TrimSpace(w.out.String())
+}
+
+// blocks renders the block children of n, each prefixed with indent.
+func (w *walker) blocks(n ast.Node, indent string) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		w.block(c, indent)
+	}
+}
+
+func (w *walker) block(n ast.Node, indent string) {
+	switch n := n.(type) {
+	case *ast.Heading:
+		line := w.inlines(n)
+		if w.style == teamsStyle {
+			line = "**" + line + "**"
+		}
+		w.para(indent + line)
+
+	case *ast.Paragraph, *ast.TextBlock:
+		w.para(indent + w.inlines(n))
+
+	case *ast.List:
+		i := n.Start
+		if i == 0 {
+			i = 1
+		}
+		for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+			marker := "- "
+			if n.IsOrdered() {
+				marker = strconv.Itoa(i) + ". "
+				i++
+			}
+			w.listItem(item, indent, marker)
+		}
+		w.out.WriteString("\n")
+
+	case *ast.Blockquote:
+		w.blocks(n, indent+"> ")
+
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		var code strings.Builder
+		lines := n.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			seg := lines.At(i)
+			code.Write(seg.Value(w.src))
+		}
+		body := strings.TrimRight(code.String(), "\n")
+		if w.style == teamsStyle {
+			body = "```\n" + body + "\n```"
+		}
+		w.para(indent + body)
+
+	case *ast.ThematicBreak:
+		if w.style == teamsStyle {
+			w.para(indent + "---")
+		}
+
+	case *east.Table:
+		for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+			var cells []string
+			for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+				cells = append(cells, w.inlines(cell))
+			}
+			w.out.WriteString(indent + strings.Join(cells, " | ") + "\n")
+		}
+		w.out.WriteString("\n")
+
+	case *ast.HTMLBlock:
+		// raw HTML is never passed through
+
+	default:
+		w.blocks(n, indent)
+	}
+}
+
+func (w *walker) listItem(item ast.Node, indent, marker string) {
+	first := true
+	for c := item.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c.(type) {
+		case *ast.List:
+			sub := &walker{src: w.src, style: w.style}
+			sub.block(c, indent+"  ")
+			w.out.WriteString(strings.TrimRight(sub.out.String(), "\n") + "\n")
+		default:
+			prefix := indent + strings.Repeat(" ", len(marker))
+			if first {
+				prefix = indent + marker
+				first = false
+			}
+			w.out.WriteString(prefix + w.inlines(c) + "\n")
+		}
+	}
+}
+
+func (w *walker) para(s string) {
+	w.out.WriteString(s + "\n\n")
+}
+
+// inlines flattens the inline children of n into one string.
+func (w *walker) inlines(n ast.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		w.inline(&b, c)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func (w *walker) inline(b *strings.Builder, n ast.Node) {
+	teams := w.style == teamsStyle
+
+	switch n := n.(type) {
+	case *ast.Text:
+		b.Write(n.Segment.Value(w.src))
+		if n.HardLineBreak() || n.SoftLineBreak() {
+			b.WriteString("\n")
+		}
+
+	case *ast.String:
+		b.Write(n.Value)
+
+	case *ast.CodeSpan:
+		if teams {
+			b.WriteString("`" + w.inlines(n) + "`")
+		} else {
+			b.WriteString(w.inlines(n))
+		}
+
+	case *ast.Emphasis:
+		mark := strings.Repeat("*", n.Level)
+		if teams {
+			b.WriteString(mark + w.inlines(n) + mark)
+		} else {
+			b.WriteString(w.inlines(n))
+		}
+
+	case *east.Strikethrough:
+		if teams {
+			b.WriteString("~~" + w.inlines(n) + "~~")
+		} else {
+			b.WriteString(w.inlines(n))
+		}
+
+	case *ast.Link:
+		w.link(b, w.inlines(n), string(n.Destination))
+
+	case *ast.AutoLink:
+		url := string(n.URL(w.src))
+		w.link(b, string(n.Label(w.src)), url)
+
+	case *ast.Image:
+		w.link(b, w.inlines(n), string(n.Destination))
+
+	case *ast.RawHTML:
+		// dropped
+
+	default:
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			w.inline(b, c)
+		}
+	}
+}
+
+func (w *walker) link(b *strings.Builder, label, url string) {
+	if !safeURL(url) {
+		b.WriteString(label)
+		return
+	}
+	switch {
+	case w.style == teamsStyle:
+		b.WriteString("[" + label + "](" + url + ")")
+	case label == "" || label == url:
+		b.WriteString(url)
+	default:
+		b.WriteString(label + " (" + url + ")")
+	}
+}
+
+// safeURL keeps javascript: and other script schemes out of text output.
+func safeURL(url string) bool {
+	lower := strings.ToLower(strings.TrimSpace(url))
+	for _, scheme := range []string{"http://", "https://", "mailto:"} {
+		if strings.HasPrefix(lower, scheme) {
+			return true
+		}
+	}
+	return !strings.Contains(lower, ":")
+}
@@ -0,0 +1,105 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunk is the INSTREAM chunk size; clamd accepts any size up to its
+// StreamMaxLength in total.
+const clamdChunk = 32 << 10
+
+// Clamd scans through a clamd daemon using the INSTREAM command.
+type Clamd struct {
+	Network string // tcp or unix
+	Address string // host:port, or a socket path
+	Timeout time.Duration
+}
+
+func (c *Clamd) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	network := c.Network
+	if network == "" {
+		network = "tcp"
+	}
+
+	d := net.Dialer{Timeout: c.Timeout}
+	conn, err := d.DialContext(ctx, network, c.Address)
+	if err != nil {
+		return Result{}, fmt.Errorf("clamd: %w", err)
+	}
+	defer conn.Close()
+
+	// the whole exchange, not each read, is bounded by Timeout or ctx
+	var deadline time.Time
+	if c.Timeout > 0 {
+		deadline = time.Now().Add(c.Timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if sendErr := instream(conn, r); sendErr != nil {
+		// clamd hangs up once StreamMaxLength is exceeded; its reply says why
+		if res, err := readReply(conn); err != nil {
+			return res, err
+		}
+		return Result{}, fmt.Errorf("clamd: %w", sendErr)
+	}
+	return readReply(conn)
+}
+
+// instream sends r as length-prefixed chunks followed by a zero-length
+// chunk.
+func instream(w io.Writer, r io.Reader) error {
+	if _, err := io.WriteString(w, "zINSTREAM\x00"); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 4+clamdChunk)
+	for {
+		n, err := r.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, werr := w.Write(buf[:4+n]); werr != nil {
+				return werr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// readReply parses "stream: OK", "stream: <signature> FOUND" or
+// "<message> ERROR".
+func readReply(r io.Reader) (Result, error) {
+	line, err := bufio.NewReader(r).ReadString(0)
+	if err != nil && line == "" {
+		return Result{}, fmt.Errorf("clamd: reading reply: %w", err)
+	}
+	reply := strings.TrimSpace(strings.TrimRight(line, "\x00"))
+	reply = strings.TrimPrefix(reply, "stream: ")
+
+	switch {
+	case reply == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return Result{Infected: true, Signature: strings.TrimSuffix(reply, " FOUND")}, nil
+	default:
+		return Result{}, fmt.Errorf("clamd: %s", reply)
+	}
+}
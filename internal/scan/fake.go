@@ -0,0 +1,38 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// EICAR is the industry-standard antivirus test file.
+const EICAR = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// Fake flags content containing any of its patterns. Setting Err makes
+// every scan fail, as when the daemon is down.
+type Fake struct {
+	Patterns map[string]string // content substring -> signature name
+	Err      error
+}
+
+// NewFake recognises the EICAR test file.
+func NewFake() *Fake {
+	return &Fake{Patterns: map[string]string{EICAR: "Eicar-Test-Signature"}}
+}
+
+func (f *Fake) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	if f.Err != nil {
+		return Result{}, f.Err
+	}
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return Result{}, err
+	}
+	for pattern, signature := range f.Patterns {
+		if bytes.Contains(content, []byte(pattern)) {
+			return Result{Infected: true, Signature: signature}, nil
+		}
+	}
+	return Result{}, nil
+}
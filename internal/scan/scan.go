@@ -0,0 +1,20 @@
+// Package scan checks uploaded files for malware. Clamd talks to a ClamAV
+// daemon; Fake recognises fixed patterns and is meant for tests.
+package scan
+
+import (
+	"context"
+	"io"
+)
+
+// Result is a scanner's verdict on one file.
+type Result struct {
+	Infected  bool
+	Signature string // name of the matched signature when Infected
+}
+
+// Scanner reads r to the end, or until it has a verdict. An error means no
+// verdict was reached and the file must be treated as unscanned.
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (Result, error)
+}
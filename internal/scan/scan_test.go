@@ -0,0 +1,115 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd accepts one INSTREAM session, hands the streamed content to
+// reply and writes back its answer.
+func fakeClamd(t *testing.T, reply func(content []byte) string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		cmd, _ := r.ReadString(0)
+		if cmd != "zINSTREAM\x00" {
+			io.WriteString(conn, "UNKNOWN COMMAND\x00")
+			return
+		}
+
+		var content bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&content, r, int64(size)); err != nil {
+				return
+			}
+		}
+		io.WriteString(conn, reply(content.Bytes())+"\x00")
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestClamdScan(t *testing.T) {
+	verdict := func(content []byte) string {
+		if bytes.Contains(content, []byte(EICAR)) {
+			return "stream: Eicar-Test-Signature FOUND"
+		}
+		return "stream: OK"
+	}
+
+	// larger than one chunk, to exercise the framing
+	clean := strings.Repeat("quarterly results ", 4000)
+
+	c := &Clamd{Address: fakeClamd(t, verdict), Timeout: 5 * time.Second}
+	res, err := c.Scan(context.Background(), strings.NewReader(clean))
+	if err != nil || res.Infected {
+		t.Fatalf("clean file: %+v %v", res, err)
+	}
+
+	c = &Clamd{Address: fakeClamd(t, verdict), Timeout: 5 * time.Second}
+	res, err = c.Scan(context.Background(), strings.NewReader(clean+EICAR))
+	if err != nil || !res.Infected || res.Signature != "Eicar-Test-Signature" {
+		t.Fatalf("eicar: %+v %v", res, err)
+	}
+}
+
+func TestClamdError(t *testing.T) {
+	addr := fakeClamd(t, func([]byte) string { return "INSTREAM size limit exceeded. ERROR" })
+
+	c := &Clamd{Address: addr, Timeout: 5 * time.Second}
+	if _, err := c.Scan(context.Background(), strings.NewReader("x")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestClamdUnreachable(t *testing.T) {
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := ln.Addr().String()
+	ln.Close()
+
+	c := &Clamd{Address: addr, Timeout: time.Second}
+	if _, err := c.Scan(context.Background(), strings.NewReader("x")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestFake(t *testing.T) {
+	f := NewFake()
+	if res, _ := f.Scan(context.Background(), strings.NewReader("hello")); res.Infected {
+		t.Fatal("clean content flagged")
+	}
+	if res, _ := f.Scan(context.Background(), strings.NewReader("a "+EICAR)); !res.Infected {
+		t.Fatal("eicar not flagged")
+	}
+
+	f.Err = io.ErrUnexpectedEOF
+	if _, err := f.Scan(context.Background(), strings.NewReader("hello")); err == nil {
+		t.Fatal("expected error")
+	}
+}
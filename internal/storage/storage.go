@@ -0,0 +1,62 @@
+// Package storage keeps attachment bytes out of the database. Objects are
+// addressed by an opaque key chosen by the caller; Local writes them under a
+// directory and S3 to any S3-compatible bucket.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// Store holds immutable objects. Put must not leave a partial object
+// behind when r fails part way.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Config selects and configures a backend.
+type Config struct {
+	Backend  string // local | s3
+	LocalDir string
+
+	S3Bucket         string
+	S3Region         string
+	S3Endpoint       string // non-AWS endpoints, e.g. MinIO
+	S3ForcePathStyle bool
+}
+
+// New builds the backend named by cfg.Backend.
+func New(ctx context.Context, cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocal(cfg.LocalDir)
+	case "s3":
+		return NewS3(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("storage: unknown backend %q", cfg.Backend)
+	}
+}
+
+// checkKey accepts slash-separated keys without empty, "." or ".." segments
+// so that no backend can be walked outside its root.
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
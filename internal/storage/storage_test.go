@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Put(ctx, "tenant/agenda.pdf", strings.NewReader("%PDF-1.7"), 8, "application/pdf"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	rc, err := store.Open(ctx, "tenant/agenda.pdf")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(got) != "%PDF-1.7" {
+		t.Fatalf("got %q", got)
+	}
+
+	if err := store.Delete(ctx, "tenant/agenda.pdf"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Open(ctx, "tenant/agenda.pdf"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("open after delete: %v", err)
+	}
+	if err := store.Delete(ctx, "tenant/agenda.pdf"); err != nil {
+		t.Fatalf("second delete: %v", err)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestLocalPutLeavesNothingOnError(t *testing.T) {
+	root := t.TempDir()
+	store, _ := NewLocal(root)
+
+	r := io.MultiReader(strings.NewReader("partial"), failingReader{})
+	if err := store.Put(context.Background(), "a/b", r, 100, "text/plain"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(root, "a"))
+	if len(entries) != 0 {
+		t.Fatalf("left behind %v", entries)
+	}
+}
+
+func TestCheckKey(t *testing.T) {
+	for _, key := range []string{"", "/etc/passwd", "../x", "a/../../x", "a//b", "a\\b", "a/./b"} {
+		if checkKey(key) == nil {
+			t.Errorf("accepted %q", key)
+		}
+	}
+	for _, key := range []string{"a", "tenant/2025/0f1e.pdf"} {
+		if err := checkKey(key); err != nil {
+			t.Errorf("rejected %q: %v", key, err)
+		}
+	}
+}
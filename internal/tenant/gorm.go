@@ -0,0 +1,93 @@
+package tenant
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const column = "tenant_id"
+
+// Register installs callbacks that filter queries, updates and deletes on
+// tenant-owned models by the context's tenant, and stamp it on inserts.
+// Raw SQL and statements without a model are not touched, and neither are
+// sessions without a tenant, such as the broadcast worker's claim loop.
+func Register(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("tenant:create", stamp); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", scope); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", scope); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:delete", scope); err != nil {
+		return err
+	}
+	return cb.Row().Before("gorm:row").Register("tenant:row", scope)
+}
+
+// Scoped returns db bound to tenant id.
+func Scoped(db *gorm.DB, id uuid.UUID) *gorm.DB {
+	return db.WithContext(WithID(db.Statement.Context, id))
+}
+
+// Unscoped returns db, or a transaction, with the tenant scope removed.
+// Only use it for data that is deliberately shared across tenants.
+func Unscoped(db *gorm.DB) *gorm.DB {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return db.WithContext(WithID(ctx, uuid.Nil))
+}
+
+func tenantField(db *gorm.DB) (uuid.UUID, bool) {
+	if db.Statement.Schema == nil {
+		return uuid.Nil, false
+	}
+	if db.Statement.Schema.LookUpField(column) == nil {
+		return uuid.Nil, false
+	}
+	return IDFrom(db.Statement.Context)
+}
+
+func scope(db *gorm.DB) {
+	id, ok := tenantField(db)
+	if !ok {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Value: id},
+	}})
+}
+
+func stamp(db *gorm.DB) {
+	id, ok := tenantField(db)
+	if !ok {
+		return
+	}
+	field := db.Statement.Schema.LookUpField(column)
+	ctx := db.Statement.Context
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			row := reflect.Indirect(rv.Index(i))
+			if err := field.Set(ctx, row, id); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := field.Set(ctx, rv, id); err != nil {
+			db.AddError(err)
+		}
+	}
+}
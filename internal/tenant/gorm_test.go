@@ -0,0 +1,76 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type owned struct {
+	ID       int
+	TenantID uuid.UUID
+	Name     string
+}
+
+type shared struct {
+	ID   int
+	Name string
+}
+
+// dryRun builds SQL without a database so the callbacks can be checked.
+func dryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=invalid"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, Register(db))
+	return db
+}
+
+func TestScopedQueries(t *testing.T) {
+	db := dryRun(t)
+	id := uuid.New()
+	scoped := Scoped(db, id)
+
+	stmt := scoped.Where("name = ?", "x").Find(&[]owned{}).Statement
+	assert.Contains(t, stmt.SQL.String(), `"owneds"."tenant_id" = $2`)
+	assert.Contains(t, stmt.Vars, any(id))
+
+	stmt = scoped.Model(&owned{}).Where("id = ?", 1).Update("name", "y").Statement
+	assert.Contains(t, stmt.SQL.String(), "tenant_id")
+
+	stmt = scoped.Where("id = ?", 1).Delete(&owned{}).Statement
+	assert.Contains(t, stmt.SQL.String(), "tenant_id")
+
+	stmt = scoped.Find(&[]shared{}).Statement
+	assert.NotContains(t, stmt.SQL.String(), "tenant_id", "models without TenantID are shared")
+
+	stmt = Unscoped(scoped).Find(&[]owned{}).Statement
+	assert.NotContains(t, stmt.SQL.String(), "tenant_id")
+
+	stmt = db.WithContext(context.Background()).Find(&[]owned{}).Statement
+	assert.NotContains(t, stmt.SQL.String(), "tenant_id", "no tenant in context")
+}
+
+func TestCreateStampsTenant(t *testing.T) {
+	db := dryRun(t)
+	id := uuid.New()
+
+	row := owned{Name: "x", TenantID: uuid.New()}
+	Scoped(db, id).Create(&row)
+	assert.Equal(t, id, row.TenantID, "context tenant wins over the caller's value")
+
+	rows := []owned{{Name: "a"}, {Name: "b"}}
+	Scoped(db, id).Create(&rows)
+	for _, r := range rows {
+		assert.Equal(t, id, r.TenantID)
+	}
+}
@@ -0,0 +1,86 @@
+package tenant
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"events-service/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ContextKey is the gin context key holding the request's *Tenant.
+const ContextKey = "tenant"
+
+// Middleware resolves the request's tenant from the caller's credential,
+// or to fallback when the credential names none and fallback is set. The
+// Host header never picks the tenant: a credential presented on another
+// tenant's host is rejected. The principal then carries the resolved
+// tenant. It must run after auth.Middleware.
+func Middleware(store Store, fallback uuid.UUID) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, authenticated := auth.FromContext(c)
+
+		id := fallback
+		if authenticated && p.Tenant != uuid.Nil {
+			id = p.Tenant
+		}
+		if id == uuid.Nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "credential names no tenant"})
+			return
+		}
+
+		t, err := store.TenantByID(id)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+
+		if host := hostname(c.Request.Host); host != "" {
+			fromHost, err := store.TenantByHost(host)
+			if err != nil && !errors.Is(err, ErrUnknownTenant) {
+				abort(c, err)
+				return
+			}
+			if fromHost != nil && fromHost.ID != t.ID {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "credential is not valid for this tenant"})
+				return
+			}
+		}
+
+		if authenticated {
+			p.Tenant = t.ID
+		}
+		c.Set(ContextKey, t)
+		c.Next()
+	}
+}
+
+// FromContext returns the Tenant stored by Middleware.
+func FromContext(c *gin.Context) (*Tenant, bool) {
+	v, ok := c.Get(ContextKey)
+	if !ok {
+		return nil, false
+	}
+	t, ok := v.(*Tenant)
+	return t, ok && t != nil
+}
+
+func abort(c *gin.Context, err error) {
+	if errors.Is(err, ErrUnknownTenant) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "unknown tenant"})
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "cannot resolve tenant"})
+}
+
+func hostname(hostport string) string {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
@@ -0,0 +1,53 @@
+// Package tenant isolates client organisations sharing one deployment. The
+// current tenant travels in the request context; Register makes GORM scope
+// every query on a model with a TenantID field to it.
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// DefaultID is the tenant that owned all rows before multi-tenancy.
+var DefaultID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+var (
+	ErrNoTenant      = errors.New("tenant: no tenant in context")
+	ErrUnknownTenant = errors.New("tenant: unknown tenant")
+)
+
+// Tenant is a client organisation and the identities its broadcasts are
+// sent from.
+type Tenant struct {
+	ID                 uuid.UUID
+	Slug               string
+	Name               string
+	Host               string
+	SESSource          string // From address for email broadcasts
+	FCMCredentialsFile string // Firebase service account for push
+	FCMTopic           string
+}
+
+// Store looks tenants up for Middleware.
+type Store interface {
+	TenantByID(id uuid.UUID) (*Tenant, error)
+	TenantByHost(host string) (*Tenant, error)
+}
+
+type ctxKey struct{}
+
+// WithID returns ctx scoped to tenant id. uuid.Nil clears the scope.
+func WithID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// IDFrom returns the tenant ctx is scoped to.
+func IDFrom(ctx context.Context) (uuid.UUID, bool) {
+	if ctx == nil {
+		return uuid.Nil, false
+	}
+	id, ok := ctx.Value(ctxKey{}).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
@@ -0,0 +1,83 @@
+package tenant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"events-service/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore map[uuid.UUID]*Tenant
+
+func (s fakeStore) TenantByID(id uuid.UUID) (*Tenant, error) {
+	if t, ok := s[id]; ok {
+		return t, nil
+	}
+	return nil, ErrUnknownTenant
+}
+
+func (s fakeStore) TenantByHost(host string) (*Tenant, error) {
+	for _, t := range s {
+		if t.Host == host {
+			return t, nil
+		}
+	}
+	return nil, ErrUnknownTenant
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	acme := &Tenant{ID: uuid.New(), Slug: "acme", Host: "acme.events.example.com"}
+	globex := &Tenant{ID: uuid.New(), Slug: "globex", Host: "globex.events.example.com"}
+	store := fakeStore{acme.ID: acme, globex.ID: globex, DefaultID: {ID: DefaultID, Slug: "eyepax"}}
+
+	run := func(fallback uuid.UUID, host string, tokenTenant uuid.UUID) (int, string) {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			c.Set(auth.PrincipalKey, &auth.Principal{Subject: uuid.New(), Tenant: tokenTenant})
+		}, Middleware(store, fallback))
+		r.GET("/x", func(c *gin.Context) {
+			tn, _ := FromContext(c)
+			c.String(http.StatusOK, tn.Slug)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/x", nil)
+		req.Host = host
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code, w.Body.String()
+	}
+
+	code, _ := run(uuid.Nil, "acme.events.example.com:443", uuid.Nil)
+	assert.Equal(t, http.StatusForbidden, code, "the host never picks the tenant")
+
+	code, slug := run(uuid.Nil, "api.internal", globex.ID)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "globex", slug, "resolved from token")
+
+	code, slug = run(uuid.Nil, "globex.events.example.com", globex.ID)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "globex", slug, "token on its own tenant's host")
+
+	code, _ = run(uuid.Nil, "acme.events.example.com", globex.ID)
+	assert.Equal(t, http.StatusForbidden, code, "token for another tenant's host")
+
+	code, _ = run(uuid.Nil, "localhost:8080", uuid.Nil)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, slug = run(DefaultID, "localhost:8080", uuid.Nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "eyepax", slug, "fallback tenant")
+
+	code, _ = run(DefaultID, "acme.events.example.com", uuid.Nil)
+	assert.Equal(t, http.StatusForbidden, code, "fallback tenant on another tenant's host")
+
+	code, _ = run(uuid.Nil, "api.internal", uuid.New())
+	assert.Equal(t, http.StatusBadRequest, code, "token names an unknown tenant")
+}
@@ -0,0 +1,66 @@
+package urlsign
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	old, _ := NewKey()
+	cur, _ := NewKey()
+	other, _ := NewKey()
+	old.Tenant, cur.Tenant, other.Tenant = "acme", "acme", "globex"
+	keys := map[string][]byte{"acme/" + old.ID: old.Secret, "acme/" + cur.ID: cur.Secret, "globex/" + other.ID: other.Secret}
+	lookup := func(tenant, id string) ([]byte, error) {
+		if s, ok := keys[tenant+"/"+id]; ok {
+			return s, nil
+		}
+		return nil, ErrUnknownKey
+	}
+
+	now := time.Unix(1_700_000_000, 0)
+	path := "/api/v1/attachments/42"
+	link := Sign(old, path, url.Values{"variant": {"thumb"}}, now.Add(time.Hour))
+
+	check := func(link string, at time.Time) error {
+		u, err := url.Parse(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return Verify(u.Path, u.Query(), at, lookup)
+	}
+
+	if err := check(link, now); err != nil {
+		t.Fatalf("fresh link: %v", err)
+	}
+	// signed before a rotation, still valid while its key is known
+	if err := check(Sign(cur, path, nil, now.Add(time.Hour)), now); err != nil {
+		t.Fatalf("current key: %v", err)
+	}
+	if err := check(link, now.Add(2*time.Hour)); !errors.Is(err, ErrExpired) {
+		t.Errorf("expired: %v", err)
+	}
+	if err := check(strings.Replace(link, "thumb", "large", 1), now); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("tampered variant: %v", err)
+	}
+	if err := check(strings.Replace(link, "/42", "/43", 1), now); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("other attachment: %v", err)
+	}
+	if err := check(path+"?variant=thumb", now); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("unsigned: %v", err)
+	}
+	if err := check(strings.Replace(link, "tid=acme", "tid=globex", 1), now); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("other tenant: %v", err)
+	}
+	if err := check(Sign(other, path, nil, now.Add(time.Hour)), now); err != nil {
+		t.Errorf("other tenant's own link: %v", err)
+	}
+
+	delete(keys, "acme/"+old.ID)
+	if err := check(link, now); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("retired key: %v", err)
+	}
+}
@@ -23,6 +23,7 @@ export default function () {
   const params = {
     headers: {
       "Content-Type": "application/json",
+      Authorization: `Bearer ${__ENV.TOKEN}`,
     },
   };
 
@@ -10,7 +10,9 @@ const BASE_URL = "http://localhost:8085/api/v1";
 const TEST_EVENT_ID = "c87f85ad-eda7-473b-bd3d-86dbf7b8c30e";
 
 export default function () {
-  const res = http.get(`${BASE_URL}/events/${TEST_EVENT_ID}`);
+  const res = http.get(`${BASE_URL}/events/${TEST_EVENT_ID}`, {
+    headers: { Authorization: `Bearer ${__ENV.TOKEN}` },
+  });
 
   check(res, {
     "status is 200": (r) => r.status === 200,
@@ -9,7 +9,9 @@ export let options = {
 const BASE_URL = "http://localhost:8085/api/v1";
 
 export default function () {
-  const res = http.get(`${BASE_URL}/events`);
+  const res = http.get(`${BASE_URL}/events`, {
+    headers: { Authorization: `Bearer ${__ENV.TOKEN}` },
+  });
 
   check(res, {
     "status is 200": (r) => r.status === 200,
@@ -19,13 +19,13 @@ const BASE_URL = "http://localhost:8085/api/v1";
 export default function () {
   const payload = JSON.stringify({
     status: "approve",
-    moderator_id: "7fcd6769-617b-4454-b6c7-2d15cddf9c11", // your real admin ID
     notes: `Approved in k6 run at ..`,
   });
 
   const params = {
     headers: {
       "Content-Type": "application/json",
+      Authorization: `Bearer ${__ENV.TOKEN}`,
     },
   };
 
@@ -27,7 +27,10 @@ export default function () {
   });
 
   const params = {
-    headers: { "Content-Type": "application/json" },
+    headers: {
+      "Content-Type": "application/json",
+      Authorization: `Bearer ${__ENV.TOKEN}`,
+    },
   };
 
   const res = http.patch(`${BASE_URL}/events/${EVENT_ID}`, payload, params);
@@ -15,13 +15,13 @@ export default function () {
     body: "A system-wide maintenance will take place on Saturday from 10 PM to 1 AM. During this window, logins, room bookings, shift updates, and leave submissions will be temporarily unavailable.",
     attachments: [],
     tags: ["maintenance", "system", "downtime"],
-    created_by: "7fcd6769-617b-4454-b6c7-2d15cddf9c11",
     scheduled_at: "2025-12-01T18:30:00Z",
   });
 
   const params = {
     headers: {
       "Content-Type": "application/json",
+      Authorization: `Bearer ${__ENV.TOKEN}`,
     },
   };
 
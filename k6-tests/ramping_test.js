@@ -12,6 +12,8 @@ export let options = {
 const BASE_URL = "http://localhost:8085/api/v1";
 
 export default function () {
-  http.get(`${BASE_URL}/events`);
+  http.get(`${BASE_URL}/events`, {
+    headers: { Authorization: `Bearer ${__ENV.TOKEN}` },
+  });
   sleep(0.5);
 }